@@ -0,0 +1,615 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"github.com/rs/zerolog/log"
+	"github.com/slack-go/slack"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// workflowPollInterval is how often a dispatched run is checked for updates
+const workflowPollInterval = 15 * time.Second
+
+// workflowRunTimeout stops following a run that takes longer
+const workflowRunTimeout = 6 * time.Hour
+
+// failureLogLines is the number of log lines shown for a failed step
+const failureLogLines = 40
+
+// maxRunLogsSize limits the size of the run logs archive that is downloaded,
+// it is kept in a temporary file
+const maxRunLogsSize = 200 << 20
+
+// runLogsTimeout limits the download of the run logs archive
+const runLogsTimeout = 2 * time.Minute
+
+func (g GithubActions) actOnWorkflow() (bool, []string, string, error) {
+	var workflowList []string
+	stat, message, err := g.validateRepoAndOrg()
+	if !stat {
+		return false, workflowList, message, fmt.Errorf("invalid org/repo. Error: %s", err)
+	}
+	stat, err = g.validateInputs()
+	if !stat {
+		return false, workflowList, "Unknown Options", fmt.Errorf("unknown inputs, Error:%s", err)
+	}
+	switch {
+	case g.Workflow.Action == "list":
+		workflowList, message, err = g.listWorkflows()
+	case g.Workflow.Action == "status":
+		var run *github.WorkflowRun
+		run, err = g.getWorkflowRun(g.Workflow.RunID)
+		if err == nil {
+			message, workflowList, err = g.workflowRunStatus(run)
+		}
+	default:
+		return false, workflowList, "", fmt.Errorf("unknown Action")
+	}
+	if err != nil {
+		return false, workflowList, message, err
+	}
+	return true, workflowList, message, nil
+}
+
+func (g GithubActions) listWorkflows() ([]string, string, error) {
+	var workflowList []string
+	lstopt := &github.ListOptions{
+		Page:    1,
+		PerPage: 100,
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	for {
+		workflows, resp, err := client.Actions.ListWorkflows(ctx, g.Organization, g.Repository, lstopt)
+		if err != nil {
+			return nil, "Internal Error", fmt.Errorf("getting workflows failed, Error: %s", err)
+		}
+		for _, workflow := range workflows.Workflows {
+			workflowList = append(workflowList, fmt.Sprintf("*<%s|%s>*\t`%s`\t%s\n", workflow.GetHTMLURL(), workflow.GetName(), path.Base(workflow.GetPath()), workflow.GetState()))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		lstopt.Page = resp.NextPage
+	}
+	if len(workflowList) == 0 {
+		return workflowList, fmt.Sprintf("No workflows found in `%s/%s`", g.Organization, g.Repository), nil
+	}
+	return workflowList, fmt.Sprintf("%d workflow/s found", len(workflowList)), nil
+}
+
+// workflowRunPreview describes `workflow run` for the confirmation message.
+func (g GithubActions) workflowRunPreview() string {
+	ref := g.Workflow.Ref
+	if len(ref) == 0 {
+		ref = "the default branch"
+	} else {
+		ref = fmt.Sprintf("`%s`", ref)
+	}
+	var inputs []string
+	for name, value := range g.Workflow.Inputs {
+		inputs = append(inputs, fmt.Sprintf("\t• `%s` = `%v`\n", name, value))
+	}
+	sort.Strings(inputs)
+	if len(inputs) == 0 {
+		return fmt.Sprintf("Run workflow `%s` on %s of `%s/%s` without inputs?", g.Workflow.File, ref, g.Organization, g.Repository)
+	}
+	return fmt.Sprintf("Run workflow `%s` on %s of `%s/%s` with these inputs?\n%s", g.Workflow.File, ref, g.Organization, g.Repository, strings.Join(inputs, ""))
+}
+
+// isWorkflowRunAllowed reports whether the Slack user is listed in
+// WORKFLOW_ALLOWED_SLACK_USERS.
+func isWorkflowRunAllowed(slackUser string) bool {
+	if len(slackUser) == 0 {
+		return false
+	}
+	for _, user := range strings.Split(os.Getenv("WORKFLOW_ALLOWED_SLACK_USERS"), ",") {
+		if strings.TrimSpace(user) == slackUser {
+			return true
+		}
+	}
+	return false
+}
+
+// claimedRuns are the runs runWorkflow already returned, by run id, so a run
+// is never taken for two dispatches. workflowDispatchMu serializes the
+// dispatches of this process with the lookup of their run.
+var (
+	workflowDispatchMu sync.Mutex
+	claimedRuns        = make(map[int64]bool)
+)
+
+// runWorkflow dispatches the workflow and returns the run it created.
+func (g GithubActions) runWorkflow() (*github.WorkflowRun, error) {
+	stat, message, err := g.validateRepoAndOrg()
+	if !stat {
+		return nil, fmt.Errorf("invalid org/repo. %s Error: %s", message, err)
+	}
+	stat, err = g.validateInputs()
+	if !stat {
+		return nil, fmt.Errorf("unknown inputs, Error:%s", err)
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	ref := g.Workflow.Ref
+	if len(ref) == 0 {
+		repo, _, err := client.Repositories.Get(ctx, g.Organization, g.Repository)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the default branch of `%s/%s`. Error: %s", g.Organization, g.Repository, err)
+		}
+		ref = repo.GetDefaultBranch()
+	}
+	// the run is created by the user of the bot token
+	actor, _, err := client.Users.Get(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the GitHub user of the bot. Error: %s", err)
+	}
+	workflowDispatchMu.Lock()
+	defer workflowDispatchMu.Unlock()
+	// allow for clock skew with GitHub, claimedRuns skips older dispatches
+	dispatchedAt := time.Now().Add(-time.Minute)
+	_, err = client.Actions.CreateWorkflowDispatchEventByFileName(ctx, g.Organization, g.Repository, g.Workflow.File, github.CreateWorkflowDispatchEventRequest{
+		Ref:    ref,
+		Inputs: g.Workflow.Inputs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to run workflow `%s` on `%s`. Error: %s", g.Workflow.File, ref, err)
+	}
+	// The dispatch API does not return the run, look for the newest run of
+	// the workflow the bot dispatched after the dispatch that is not the run
+	// of an earlier dispatch.
+	opts := &github.ListWorkflowRunsOptions{
+		Actor:       actor.GetLogin(),
+		Event:       "workflow_dispatch",
+		Branch:      ref,
+		Created:     ">=" + dispatchedAt.UTC().Format(time.RFC3339),
+		ListOptions: github.ListOptions{PerPage: 20},
+	}
+	for i := 0; i < 10; i++ {
+		time.Sleep(3 * time.Second)
+		runs, _, err := client.Actions.ListWorkflowRunsByFileName(ctx, g.Organization, g.Repository, g.Workflow.File, opts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to find the run of workflow `%s`. Error: %s", g.Workflow.File, err)
+		}
+		for _, run := range runs.WorkflowRuns {
+			if !claimedRuns[run.GetID()] {
+				claimedRuns[run.GetID()] = true
+				return run, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("workflow `%s` was dispatched on `%s` but its run did not show up yet, msg me `workflow list` to find it", g.Workflow.File, ref)
+}
+
+func (g GithubActions) getWorkflowRun(runID int64) (*github.WorkflowRun, error) {
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	run, _, err := client.Actions.GetWorkflowRunByID(ctx, g.Organization, g.Repository, runID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get workflow run `%d`. Error: %s", runID, err)
+	}
+	return run, nil
+}
+
+// workflowRunStatus describes the run and, once it failed, links the
+// failed jobs and steps.
+func (g GithubActions) workflowRunStatus(run *github.WorkflowRun) (string, []string, error) {
+	var failures []string
+	state := run.GetStatus()
+	if state == "completed" {
+		state = run.GetConclusion()
+	}
+	message := fmt.Sprintf("%s *<%s|%s #%d>* on `%s`: `%s`", runEmoji(run), run.GetHTMLURL(), run.GetName(), run.GetRunNumber(), run.GetHeadBranch(), state)
+	if run.GetStatus() != "completed" || run.GetConclusion() == "success" {
+		return message, failures, nil
+	}
+	jobs, err := g.failedJobs(run.GetID())
+	if err != nil {
+		return message, failures, err
+	}
+	for _, job := range jobs {
+		step := failedStep(job)
+		if step == nil {
+			failures = append(failures, fmt.Sprintf(":x: job *<%s|%s>* %s\n", job.GetHTMLURL(), job.GetName(), job.GetConclusion()))
+			continue
+		}
+		failures = append(failures, fmt.Sprintf(":x: job *<%s|%s>* failed at step *<%s#step:%d:1|%s>*\n", job.GetHTMLURL(), job.GetName(), job.GetHTMLURL(), step.GetNumber(), step.GetName()))
+	}
+	return message, failures, nil
+}
+
+// failedJobs returns the jobs of the latest attempt of the run that did not
+// succeed.
+func (g GithubActions) failedJobs(runID int64) ([]*github.WorkflowJob, error) {
+	var failed []*github.WorkflowJob
+	opts := &github.ListWorkflowJobsOptions{
+		Filter: "latest",
+		ListOptions: github.ListOptions{
+			Page:    1,
+			PerPage: 100,
+		},
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	for {
+		jobs, resp, err := client.Actions.ListWorkflowJobs(ctx, g.Organization, g.Repository, runID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("getting jobs of run `%d` failed, Error: %s", runID, err)
+		}
+		for _, job := range jobs.Jobs {
+			if job.GetStatus() == "completed" && job.GetConclusion() != "success" && job.GetConclusion() != "skipped" && job.GetConclusion() != "neutral" {
+				failed = append(failed, job)
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.ListOptions.Page = resp.NextPage
+	}
+	return failed, nil
+}
+
+func failedStep(job *github.WorkflowJob) *github.TaskStep {
+	for _, step := range job.Steps {
+		if step.GetConclusion() == "failure" || step.GetConclusion() == "timed_out" {
+			return step
+		}
+	}
+	return nil
+}
+
+func runEmoji(run *github.WorkflowRun) string {
+	switch {
+	case run.GetStatus() == "queued" || run.GetStatus() == "waiting" || run.GetStatus() == "requested":
+		return ":hourglass:"
+	case run.GetStatus() != "completed":
+		return ":arrows_counterclockwise:"
+	case run.GetConclusion() == "success":
+		return ":white_check_mark:"
+	case run.GetConclusion() == "cancelled" || run.GetConclusion() == "skipped":
+		return ":heavy_minus_sign:"
+	default:
+		return ":x:"
+	}
+}
+
+// followWorkflowRun posts the status of the run to the channel and edits
+// that message every time the run moves on, until it completes.
+func (g GithubActions) followWorkflowRun(client *slack.Client, channel string, run *github.WorkflowRun) {
+	message, _, _ := g.workflowRunStatus(run)
+	_, timestamp, err := client.PostMessage(channel, slack.MsgOptionText(message, false))
+	if err != nil {
+		log.Info().Msg(fmt.Sprintf("Unable to post the status of run %d, Error: %s", run.GetID(), err))
+		return
+	}
+	lastState := run.GetStatus()
+	deadline := time.Now().Add(workflowRunTimeout)
+	for run.GetStatus() != "completed" && time.Now().Before(deadline) {
+		time.Sleep(workflowPollInterval)
+		latest, err := g.getWorkflowRun(run.GetID())
+		if err != nil {
+			log.Info().Msg(err.Error())
+			continue
+		}
+		run = latest
+		if run.GetStatus() == lastState {
+			continue
+		}
+		lastState = run.GetStatus()
+		message, failures, err := g.workflowRunStatus(run)
+		if err != nil {
+			log.Info().Msg(err.Error())
+		}
+		options := []slack.MsgOption{slack.MsgOptionText(message, false)}
+		if len(failures) > 0 {
+			options = append(options, slack.MsgOptionAttachments(slack.Attachment{Text: strings.Join(failures, "")}))
+		}
+		_, _, _, err = client.UpdateMessage(channel, timestamp, options...)
+		if err != nil {
+			log.Info().Msg(fmt.Sprintf("Unable to update the status of run %d, Error: %s", run.GetID(), err))
+		}
+	}
+}
+
+// parseWorkflowOptions parses `ref=<branch>;inputs.<key>=<value>` options.
+func parseWorkflowOptions(options string) (string, map[string]interface{}, error) {
+	inputs := make(map[string]interface{})
+	params, err := paramsFromAnnotation(options)
+	if err != nil {
+		return "", nil, fmt.Errorf("options could not be parsed: %v", err)
+	}
+	var ref string
+	for key, values := range params {
+		if len(values) == 0 {
+			return "", nil, fmt.Errorf("empty %s is not supported", key)
+		}
+		switch {
+		case strings.HasPrefix(key, "inputs.") && len(key) > len("inputs."):
+			inputs[strings.TrimPrefix(key, "inputs.")] = strings.TrimSpace(values[len(values)-1])
+		case contains(supportedWorkflowOptions, key):
+			ref = strings.TrimSpace(values[len(values)-1])
+		default:
+			return "", nil, fmt.Errorf("unrecognized option: %s", key)
+		}
+	}
+	return ref, inputs, nil
+}
+
+func (g GithubActions) actOnCI() (bool, []string, string, error) {
+	var failureList []string
+	stat, message, err := g.validateRepoAndOrg()
+	if !stat {
+		return false, failureList, message, fmt.Errorf("invalid org/repo. Error: %s", err)
+	}
+	stat, err = g.validateInputs()
+	if !stat {
+		return false, failureList, "Unknown Options", fmt.Errorf("unknown inputs, Error:%s", err)
+	}
+	switch {
+	case g.CI.Action == "failures":
+		failureList, message, err = g.ciFailures()
+	case g.CI.Action == "rerun":
+		message, err = g.rerunWorkflow()
+	default:
+		return false, failureList, "", fmt.Errorf("unknown Action")
+	}
+	if err != nil {
+		return false, failureList, message, err
+	}
+	return true, failureList, message, nil
+}
+
+// latestRuns returns the workflow runs of the newest commit of the branch,
+// or of the head commit of the pull request.
+func (g GithubActions) latestRuns() ([]*github.WorkflowRun, string, error) {
+	var latest []*github.WorkflowRun
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	branch := g.CI.Branch
+	var headSHA string
+	if g.CI.PullNumber > 0 {
+		pull, _, err := client.PullRequests.Get(ctx, g.Organization, g.Repository, g.CI.PullNumber)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to get pull request `#%d`. Error: %s", g.CI.PullNumber, err)
+		}
+		branch = pull.GetHead().GetRef()
+		headSHA = pull.GetHead().GetSHA()
+	}
+	runs, _, err := client.Actions.ListRepositoryWorkflowRuns(ctx, g.Organization, g.Repository, &github.ListWorkflowRunsOptions{
+		Branch:      branch,
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("getting workflow runs of `%s` failed, Error: %s", branch, err)
+	}
+	if len(runs.WorkflowRuns) == 0 {
+		return latest, headSHA, nil
+	}
+	// runs are returned newest first
+	if len(headSHA) == 0 {
+		headSHA = runs.WorkflowRuns[0].GetHeadSHA()
+	}
+	seen := make(map[int64]bool)
+	for _, run := range runs.WorkflowRuns {
+		if run.GetHeadSHA() != headSHA || seen[run.GetWorkflowID()] {
+			continue
+		}
+		seen[run.GetWorkflowID()] = true
+		latest = append(latest, run)
+	}
+	return latest, headSHA, nil
+}
+
+func (g GithubActions) ciFailures() ([]string, string, error) {
+	var failureList []string
+	target := fmt.Sprintf("`%s`", g.CI.Branch)
+	if g.CI.PullNumber > 0 {
+		target = fmt.Sprintf("pull request `#%d`", g.CI.PullNumber)
+	}
+	runs, headSHA, err := g.latestRuns()
+	if err != nil {
+		return nil, "Internal Error", err
+	}
+	if len(runs) == 0 {
+		return failureList, fmt.Sprintf("No workflow runs found for %s", target), nil
+	}
+	for _, run := range runs {
+		if run.GetStatus() != "completed" || run.GetConclusion() == "success" {
+			continue
+		}
+		jobs, err := g.failedJobs(run.GetID())
+		if err != nil {
+			return nil, "Internal Error", err
+		}
+		if len(jobs) == 0 {
+			continue
+		}
+		logs, err := g.runLogs(run.GetID())
+		if err != nil {
+			log.Info().Msg(err.Error())
+		}
+		for _, job := range jobs {
+			failureList = append(failureList, formatJobFailure(run, job, logs))
+		}
+	}
+	if len(failureList) == 0 {
+		return failureList, fmt.Sprintf("No failed jobs in the latest runs of %s at `%.7s` :tada:", target, headSHA), nil
+	}
+	return failureList, fmt.Sprintf("%d failed job/s in the latest runs of %s at `%.7s`", len(failureList), target, headSHA), nil
+}
+
+func formatJobFailure(run *github.WorkflowRun, job *github.WorkflowJob, logs map[string]string) string {
+	line := fmt.Sprintf(":x: *<%s|%s>* / *<%s|%s>* (run `%d`)", run.GetHTMLURL(), run.GetName(), job.GetHTMLURL(), job.GetName(), run.GetID())
+	step := failedStep(job)
+	if step == nil {
+		return line + fmt.Sprintf(" %s\n", job.GetConclusion())
+	}
+	line = line + fmt.Sprintf(" failed at step `%s`\n", step.GetName())
+	excerpt, ok := stepLog(logs, job, step)
+	if !ok {
+		return line + "_log not available_\n"
+	}
+	return line + fmt.Sprintf("```%s```\n", strings.ReplaceAll(excerpt, "```", "` ` `"))
+}
+
+// runLogs downloads the logs archive of the run, which holds a file per
+// step named `<job name>/<step number>_<step name>.txt`.
+func (g GithubActions) runLogs(runID int64) (map[string]string, error) {
+	logs := make(map[string]string)
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	logsURL, _, err := client.Actions.GetWorkflowRunLogs(ctx, g.Organization, g.Repository, runID, false)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the logs of run `%d`. Error: %s", runID, err)
+	}
+	// the logs URL is pre-signed and must be fetched without the token
+	httpClient := &http.Client{Timeout: runLogsTimeout}
+	resp, err := httpClient.Get(logsURL.String())
+	if err != nil {
+		return nil, fmt.Errorf("unable to download the logs of run `%d`. Error: %s", runID, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to download the logs of run `%d`. Status: %s", runID, resp.Status)
+	}
+	archive, err := ioutil.TempFile("", "run-logs-*.zip")
+	if err != nil {
+		return nil, fmt.Errorf("unable to store the logs of run `%d`. Error: %s", runID, err)
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+	size, err := io.Copy(archive, io.LimitReader(resp.Body, maxRunLogsSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to download the logs of run `%d`. Error: %s", runID, err)
+	}
+	if size > maxRunLogsSize {
+		return nil, fmt.Errorf("the logs of run `%d` are larger than %d MB", runID, maxRunLogsSize>>20)
+	}
+	reader, err := zip.NewReader(archive, size)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the logs of run `%d`. Error: %s", runID, err)
+	}
+	for _, file := range reader.File {
+		if !strings.Contains(file.Name, "/") {
+			continue
+		}
+		f, err := file.Open()
+		if err != nil {
+			continue
+		}
+		// only the end of a step log is shown, so only that is kept
+		content, err := lastLines(f, failureLogLines)
+		f.Close()
+		if err == nil {
+			logs[file.Name] = content
+		}
+	}
+	return logs, nil
+}
+
+// lastLines reads r to the end and returns its last count lines.
+func lastLines(r io.Reader, count int) (string, error) {
+	var lines []string
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			lines = append(lines, line)
+			if len(lines) > count {
+				lines = lines[1:]
+			}
+		}
+		if err == io.EOF {
+			return strings.Join(lines, ""), nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// stepLog returns the last failureLogLines lines of the step log.
+func stepLog(logs map[string]string, job *github.WorkflowJob, step *github.TaskStep) (string, bool) {
+	prefix := fmt.Sprintf("%s/%d_", job.GetName(), step.GetNumber())
+	for name, content := range logs {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+		if len(lines) > failureLogLines {
+			lines = lines[len(lines)-failureLogLines:]
+		}
+		return strings.Join(lines, "\n"), true
+	}
+	return "", false
+}
+
+// rerunPreview describes `ci rerun` for the confirmation message, after
+// checking the run can be re-run.
+func (g GithubActions) rerunPreview() (string, error) {
+	stat, message, err := g.validateRepoAndOrg()
+	if !stat {
+		return "", fmt.Errorf("invalid org/repo. %s Error: %s", message, err)
+	}
+	run, err := g.getWorkflowRun(g.CI.RunID)
+	if err != nil {
+		return "", err
+	}
+	if run.GetStatus() != "completed" {
+		return "", fmt.Errorf("run `%d` is still `%s`, it can be re-run once it completes", g.CI.RunID, run.GetStatus())
+	}
+	jobs := "all jobs"
+	if g.CI.FailedOnly {
+		jobs = "the failed jobs"
+	}
+	return fmt.Sprintf("Re-run %s of *<%s|%s #%d>* on `%s` of `%s/%s`?", jobs, run.GetHTMLURL(), run.GetName(), run.GetRunNumber(), run.GetHeadBranch(), g.Organization, g.Repository), nil
+}
+
+func (g GithubActions) rerunWorkflow() (string, error) {
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	run, err := g.getWorkflowRun(g.CI.RunID)
+	if err != nil {
+		return "Unknown Run", err
+	}
+	if run.GetStatus() != "completed" {
+		return "Run In Progress", fmt.Errorf("run `%d` is still `%s`, it can be re-run once it completes", g.CI.RunID, run.GetStatus())
+	}
+	if g.CI.FailedOnly {
+		_, err = client.Actions.RerunFailedJobsByID(ctx, g.Organization, g.Repository, g.CI.RunID)
+	} else {
+		_, err = client.Actions.RerunWorkflowByID(ctx, g.Organization, g.Repository, g.CI.RunID)
+	}
+	if err != nil {
+		return "Failed to Rerun", fmt.Errorf("unable to re-run run `%d`. Error: %s", g.CI.RunID, err)
+	}
+	if g.CI.FailedOnly {
+		return fmt.Sprintf("re-running the failed jobs of *<%s|%s #%d>*", run.GetHTMLURL(), run.GetName(), run.GetRunNumber()), nil
+	}
+	return fmt.Sprintf("re-running all jobs of *<%s|%s #%d>*", run.GetHTMLURL(), run.GetName(), run.GetRunNumber()), nil
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLastLines(t *testing.T) {
+	tests := []struct {
+		content string
+		count   int
+		want    string
+	}{
+		{content: "", count: 2, want: ""},
+		{content: "a\nb\nc\n", count: 2, want: "b\nc\n"},
+		{content: "a\nb\nc", count: 2, want: "b\nc"},
+		{content: "a\nb\n", count: 5, want: "a\nb\n"},
+	}
+	for _, tt := range tests {
+		got, err := lastLines(strings.NewReader(tt.content), tt.count)
+		if err != nil || got != tt.want {
+			t.Errorf("lastLines(%q, %d) = %q, %v, want %q", tt.content, tt.count, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"github.com/rs/zerolog/log"
+	"sort"
+	"strings"
+)
+
+// auditPermissions are the permissions `collaborator audit` reports.
+var auditPermissions = []string{"admin", "maintain", "push"}
+
+func (g GithubActions) actOnCollaborator() (bool, []string, string, error) {
+	var collaboratorList []string
+	var stat bool
+	var message string
+	var err error
+	if g.Collaborator.Action == "audit" {
+		stat, message, err = g.validateOrg()
+	} else {
+		stat, message, err = g.validateRepoAndOrg()
+	}
+	if !stat {
+		return false, collaboratorList, message, fmt.Errorf("invalid org/repo. Error: %s", err)
+	}
+	stat, err = g.validateInputs()
+	if !stat {
+		return false, collaboratorList, "Unknown Options", fmt.Errorf("unknown inputs, Error:%s", err)
+	}
+	switch {
+	case g.Collaborator.Action == "list":
+		collaboratorList, message, err = g.listCollaborators()
+	case g.Collaborator.Action == "add":
+		message, err = g.addCollaborator()
+	case g.Collaborator.Action == "remove":
+		message, err = g.removeCollaborator()
+	case g.Collaborator.Action == "audit":
+		collaboratorList, message, err = g.auditCollaborators()
+	default:
+		return false, collaboratorList, "", fmt.Errorf("unknown Action")
+	}
+	if err != nil {
+		return false, collaboratorList, message, err
+	}
+	return true, collaboratorList, message, nil
+}
+
+// outsideCollaborators returns the logins of the outside collaborators of
+// the organization, filter is empty or `2fa_disabled`.
+func (g GithubActions) outsideCollaborators(filter string) (map[string]bool, error) {
+	outside := make(map[string]bool)
+	lstopt := &github.ListOutsideCollaboratorsOptions{
+		Filter: filter,
+		ListOptions: github.ListOptions{
+			Page:    1,
+			PerPage: 100,
+		},
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	for {
+		users, resp, err := client.Organizations.ListOutsideCollaborators(ctx, g.Organization, lstopt)
+		if err != nil {
+			return nil, fmt.Errorf("getting outside collaborators failed, Error: %s", err)
+		}
+		for _, user := range users {
+			outside[strings.ToLower(user.GetLogin())] = true
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		lstopt.Page = resp.NextPage
+	}
+	return outside, nil
+}
+
+// repoCollaborators returns the collaborators of a repository of the
+// organization with the given affiliation.
+func (g GithubActions) repoCollaborators(repo string, affiliation string) ([]*github.User, error) {
+	var collaborators []*github.User
+	lstopt := &github.ListCollaboratorsOptions{
+		Affiliation: affiliation,
+		ListOptions: github.ListOptions{
+			Page:    1,
+			PerPage: 100,
+		},
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	for {
+		users, resp, err := client.Repositories.ListCollaborators(ctx, g.Organization, repo, lstopt)
+		if err != nil {
+			return nil, fmt.Errorf("getting the collaborators of `%s` failed, Error: %s", repo, err)
+		}
+		collaborators = append(collaborators, users...)
+		if resp.NextPage == 0 {
+			break
+		}
+		lstopt.Page = resp.NextPage
+	}
+	return collaborators, nil
+}
+
+// orgRepos returns the repositories of the organization.
+func (g GithubActions) orgRepos() ([]*github.Repository, error) {
+	var repos []*github.Repository
+	lstopt := &github.RepositoryListByOrgOptions{
+		Type: "all",
+		ListOptions: github.ListOptions{
+			Page:    1,
+			PerPage: 100,
+		},
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	for {
+		page, resp, err := client.Repositories.ListByOrg(ctx, g.Organization, lstopt)
+		if err != nil {
+			return nil, fmt.Errorf("getting the repositories of `%s` failed, Error: %s", g.Organization, err)
+		}
+		repos = append(repos, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		lstopt.Page = resp.NextPage
+	}
+	return repos, nil
+}
+
+// listCollaborators lists the direct collaborators of the repository with
+// their permission, marking the outside collaborators.
+func (g GithubActions) listCollaborators() ([]string, string, error) {
+	var collaboratorList []string
+	outside, err := g.outsideCollaborators("")
+	if err != nil {
+		return nil, "Internal Error", err
+	}
+	collaborators, err := g.repoCollaborators(g.Repository, "direct")
+	if err != nil {
+		return nil, "Internal Error", err
+	}
+	outsideCount := 0
+	for _, user := range collaborators {
+		kind := "member"
+		if outside[strings.ToLower(user.GetLogin())] {
+			kind = "outside collaborator"
+			outsideCount++
+		}
+		collaboratorList = append(collaboratorList, fmt.Sprintf("*<%s|%s>*\t`%s`\t%s\n", user.GetHTMLURL(), user.GetLogin(), repoPermission(user.Permissions), kind))
+	}
+	if len(collaboratorList) == 0 {
+		return collaboratorList, fmt.Sprintf("`%s/%s` has no direct collaborators", g.Organization, g.Repository), nil
+	}
+	return collaboratorList, fmt.Sprintf("`%s/%s` has %d direct collaborator/s, %d of them outside collaborator/s", g.Organization, g.Repository, len(collaboratorList), outsideCount), nil
+}
+
+// addCollaborator invites the user to the repository, or changes the
+// permission of an existing collaborator.
+func (g GithubActions) addCollaborator() (string, error) {
+	if stat, reason, err := validateUser(g.Collaborator.Login, g.Organization); !stat {
+		return reason, fmt.Errorf("unable to find user `%s`. Error: %s", g.Collaborator.Login, err)
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	invitation, _, err := client.Repositories.AddCollaborator(ctx, g.Organization, g.Repository, g.Collaborator.Login, &github.RepositoryAddCollaboratorOptions{Permission: g.Collaborator.Permission})
+	if err != nil {
+		return "Failed to Add", fmt.Errorf("unable to add `%s` to `%s/%s`. Error: %s", g.Collaborator.Login, g.Organization, g.Repository, err)
+	}
+	log.Info().Msg(fmt.Sprintf("Collaborator %s added to %s/%s with %s", g.Collaborator.Login, g.Organization, g.Repository, g.Collaborator.Permission))
+	// GitHub answers without an invitation when the user already had access
+	if invitation == nil {
+		return fmt.Sprintf("`%s` now has `%s` on `%s/%s`", g.Collaborator.Login, g.Collaborator.Permission, g.Organization, g.Repository), nil
+	}
+	return fmt.Sprintf("`%s` invited to `%s/%s` with `%s`", g.Collaborator.Login, g.Organization, g.Repository, g.Collaborator.Permission), nil
+}
+
+func (g GithubActions) removeCollaborator() (string, error) {
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	isCollaborator, _, err := client.Repositories.IsCollaborator(ctx, g.Organization, g.Repository, g.Collaborator.Login)
+	if err != nil {
+		return "Internal Error", fmt.Errorf("unable to check if `%s` is a collaborator of `%s`. Error: %s", g.Collaborator.Login, g.Repository, err)
+	}
+	if !isCollaborator {
+		return "Not A Collaborator", fmt.Errorf("`%s` is not a collaborator of `%s/%s`", g.Collaborator.Login, g.Organization, g.Repository)
+	}
+	_, err = client.Repositories.RemoveCollaborator(ctx, g.Organization, g.Repository, g.Collaborator.Login)
+	if err != nil {
+		return "Failed to Remove", fmt.Errorf("unable to remove `%s` from `%s/%s`. Error: %s", g.Collaborator.Login, g.Organization, g.Repository, err)
+	}
+	log.Info().Msg(fmt.Sprintf("Collaborator %s removed from %s/%s", g.Collaborator.Login, g.Organization, g.Repository))
+	return fmt.Sprintf("`%s` removed from `%s/%s`, access through teams is not changed", g.Collaborator.Login, g.Organization, g.Repository), nil
+}
+
+// auditCollaborators reports the outside collaborators with write access or
+// more on any repository of the organization, one line per collaborator.
+func (g GithubActions) auditCollaborators() ([]string, string, error) {
+	var auditList []string
+	repos, err := g.orgRepos()
+	if err != nil {
+		return nil, "Internal Error", err
+	}
+	access := make(map[string][]string)
+	for _, repo := range repos {
+		if repo.GetArchived() {
+			continue
+		}
+		collaborators, err := g.repoCollaborators(repo.GetName(), "outside")
+		if err != nil {
+			return nil, "Internal Error", err
+		}
+		for _, user := range collaborators {
+			permission := repoPermission(user.Permissions)
+			if contains(auditPermissions, permission) {
+				access[user.GetLogin()] = append(access[user.GetLogin()], fmt.Sprintf("`%s` (%s)", repo.GetName(), permission))
+			}
+		}
+	}
+	var logins []string
+	for login := range access {
+		logins = append(logins, login)
+	}
+	sort.Strings(logins)
+	for _, login := range logins {
+		auditList = append(auditList, fmt.Sprintf("*%s*\t%s\n", login, strings.Join(access[login], ", ")))
+	}
+	if len(auditList) == 0 {
+		return auditList, fmt.Sprintf("No outside collaborator has write access or more in `%s`", g.Organization), nil
+	}
+	return auditList, fmt.Sprintf("%d outside collaborator/s have write access or more in `%s`", len(auditList), g.Organization), nil
+}
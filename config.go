@@ -0,0 +1,223 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// botConfig is the optional JSON file named by BOT_CONFIG_FILE. Repositories
+// are written as `org/repo`, or `repo` for a repository of GITHUB_ORG.
+type botConfig struct {
+	// Repositories is the allowlist of repositories commands may target, any
+	// repository of GITHUB_ORG or of Organizations is allowed when it is empty.
+	Repositories []string `json:"repositories"`
+	// ChannelDefaults and UserDefaults map Slack channel and user IDs to the
+	// repository used when a command has no `repo=` option.
+	ChannelDefaults map[string]string `json:"channel_defaults"`
+	UserDefaults    map[string]string `json:"user_defaults"`
+	// Organizations lists the organizations that need their own credentials.
+	Organizations []orgConfig `json:"organizations"`
+}
+
+// orgConfig holds the credentials of an organization, the token itself is
+// read from the environment variable named by TokenEnv.
+type orgConfig struct {
+	Name          string `json:"name"`
+	TokenEnv      string `json:"token_env"`
+	EnterpriseURL string `json:"enterprise_url"`
+}
+
+var (
+	configOnce   sync.Once
+	loadedConfig *botConfig
+	configErr    error
+)
+
+// getConfig loads BOT_CONFIG_FILE once, an unset variable is an empty config.
+func getConfig() (*botConfig, error) {
+	configOnce.Do(func() {
+		loadedConfig = &botConfig{}
+		path := os.Getenv("BOT_CONFIG_FILE")
+		if len(path) == 0 {
+			return
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			configErr = fmt.Errorf("unable to read BOT_CONFIG_FILE `%s`. Error: %s", path, err)
+			return
+		}
+		if err := json.Unmarshal(content, loadedConfig); err != nil {
+			configErr = fmt.Errorf("unable to parse BOT_CONFIG_FILE `%s`. Error: %s", path, err)
+			return
+		}
+		for _, org := range loadedConfig.Organizations {
+			if len(org.Name) == 0 || len(org.TokenEnv) == 0 {
+				configErr = fmt.Errorf("every organization in BOT_CONFIG_FILE `%s` needs a `name` and a `token_env`", path)
+				return
+			}
+			if len(os.Getenv(org.TokenEnv)) == 0 {
+				configErr = fmt.Errorf("the environment variable %s of the organization `%s` must be set", org.TokenEnv, org.Name)
+				return
+			}
+		}
+	})
+	return loadedConfig, configErr
+}
+
+// getOrgConfig returns the credentials configured for the organization, or
+// nil when it uses the GITHUB_OAUTH_TOKEN defaults.
+func getOrgConfig(org string) (*orgConfig, error) {
+	config, err := getConfig()
+	if err != nil {
+		return nil, err
+	}
+	for i := range config.Organizations {
+		if strings.EqualFold(config.Organizations[i].Name, org) {
+			return &config.Organizations[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// splitRepo splits `org/repo` into its parts, `repo` belongs to defaultOrg.
+func splitRepo(fullName string, defaultOrg string) (string, string) {
+	parts := strings.SplitN(strings.TrimSpace(fullName), "/", 2)
+	if len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return defaultOrg, parts[0]
+}
+
+// isAllowedOrg reports whether commands may act on the organization, which
+// is GITHUB_ORG, a configured organization or the organization of an
+// allowed repository.
+func isAllowedOrg(org string) bool {
+	if strings.EqualFold(org, os.Getenv("GITHUB_ORG")) {
+		return true
+	}
+	if orgConf, err := getOrgConfig(org); err != nil || orgConf != nil {
+		return orgConf != nil
+	}
+	config, err := getConfig()
+	if err != nil {
+		return false
+	}
+	for _, allowed := range config.Repositories {
+		allowedOrg, _ := splitRepo(allowed, os.Getenv("GITHUB_ORG"))
+		if strings.EqualFold(allowedOrg, org) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAllowedRepo reports whether commands may act on org/repo.
+func isAllowedRepo(org string, repo string) bool {
+	config, err := getConfig()
+	if err != nil {
+		return false
+	}
+	if len(config.Repositories) == 0 {
+		return isAllowedOrg(org)
+	}
+	for _, allowed := range config.Repositories {
+		allowedOrg, allowedRepo := splitRepo(allowed, os.Getenv("GITHUB_ORG"))
+		if strings.EqualFold(allowedOrg, org) && strings.EqualFold(allowedRepo, repo) {
+			return true
+		}
+	}
+	return false
+}
+
+// commandTarget is the organization and repository a command acts on.
+type commandTarget struct {
+	Organization string
+	Repository   string
+}
+
+// takeOptions removes the `org=` and `repo=` options from a command
+// parameter into the target and returns what is left of the parameter.
+func (t *commandTarget) takeOptions(param string) string {
+	if !strings.Contains(param, "org=") && !strings.Contains(param, "repo=") {
+		return param
+	}
+	var rest []string
+	for _, part := range strings.Split(param, ";") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) == 2 && len(strings.Fields(kv[1])) == 1 {
+			switch strings.TrimSpace(kv[0]) {
+			case "org":
+				// `org=true` is an option of member remove, not an organization
+				if _, err := strconv.ParseBool(strings.TrimSpace(kv[1])); err == nil {
+					break
+				}
+				t.Organization = strings.TrimSpace(kv[1])
+				continue
+			case "repo":
+				t.Repository = strings.TrimSpace(kv[1])
+				continue
+			}
+		}
+		rest = append(rest, part)
+	}
+	return strings.TrimSpace(strings.Join(rest, ";"))
+}
+
+// resolve fills in the target from the channel default, the user default
+// and then GITHUB_ORG/GITHUB_REPO, and checks it against the allowlist.
+// An explicit org= wins over the organization of a default `org/repo`, and
+// must match the one of an explicit repo=org/name.
+func (t commandTarget) resolve(channel string, user string) (GithubActions, error) {
+	var githubAct GithubActions
+	config, err := getConfig()
+	if err != nil {
+		return githubAct, err
+	}
+	org := os.Getenv("GITHUB_ORG")
+	repo := t.Repository
+	for _, fallback := range []string{config.ChannelDefaults[channel], config.UserDefaults[user], os.Getenv("GITHUB_REPO")} {
+		if len(repo) > 0 {
+			break
+		}
+		repo = fallback
+	}
+	if len(repo) == 0 {
+		return githubAct, fmt.Errorf("no repository given, add `repo=<name>` to the command")
+	}
+	if strings.Contains(repo, "/") {
+		org, repo = splitRepo(repo, org)
+	}
+	if len(t.Organization) > 0 {
+		// org= only replaces the organization of a default repository, an
+		// explicit repo=org/name already names one
+		if strings.Contains(t.Repository, "/") && !strings.EqualFold(org, t.Organization) {
+			return githubAct, fmt.Errorf("`org=%s` does not match `repo=%s`, give one of them", t.Organization, t.Repository)
+		}
+		org = t.Organization
+	}
+	if !isAllowedRepo(org, repo) {
+		return githubAct, fmt.Errorf("repository `%s/%s` is not in the list of repositories the bot may act on", org, repo)
+	}
+	githubAct.Organization = org
+	githubAct.Repository = repo
+	return githubAct, nil
+}
+
+// resolveOrg is resolve for commands that act on the organization only.
+func (t commandTarget) resolveOrg() (GithubActions, error) {
+	var githubAct GithubActions
+	org := t.Organization
+	if len(org) == 0 {
+		org = os.Getenv("GITHUB_ORG")
+	}
+	if !isAllowedOrg(org) {
+		return githubAct, fmt.Errorf("organization `%s` is not in the list of organizations the bot may act on", org)
+	}
+	githubAct.Organization = org
+	return githubAct, nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import "testing"
+
+func TestTakeOptions(t *testing.T) {
+	tests := []struct {
+		param    string
+		wantRest string
+		wantOrg  string
+		wantRepo string
+	}{
+		{param: "", wantRest: ""},
+		{param: "state=open;author=johns", wantRest: "state=open;author=johns"},
+		{param: "repo=backend", wantRest: "", wantRepo: "backend"},
+		{param: "org=other", wantRest: "", wantOrg: "other"},
+		{param: "repo=backend;permission=push", wantRest: "permission=push", wantRepo: "backend"},
+		{param: "permission=push;repo=backend", wantRest: "permission=push", wantRepo: "backend"},
+		{param: "repo=other-org/backend;org=x", wantRest: "", wantOrg: "x", wantRepo: "other-org/backend"},
+		{param: "team=storage;org=true", wantRest: "team=storage;org=true"},
+		{param: "org=false", wantRest: "org=false"},
+		{param: "title=move the repo=x option", wantRest: "title=move the repo=x option"},
+		{param: "label=bug; repo=backend ;state=open", wantRest: "label=bug;state=open", wantRepo: "backend"},
+	}
+	for _, tt := range tests {
+		target := &commandTarget{}
+		rest := target.takeOptions(tt.param)
+		if rest != tt.wantRest || target.Organization != tt.wantOrg || target.Repository != tt.wantRepo {
+			t.Errorf("takeOptions(%q) = %q, org %q, repo %q, want %q, org %q, repo %q", tt.param, rest, target.Organization, target.Repository, tt.wantRest, tt.wantOrg, tt.wantRepo)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("BOT_CONFIG_FILE", "")
+	t.Setenv("GITHUB_ORG", "acme")
+	tests := []struct {
+		target      commandTarget
+		defaultRepo string
+		wantOrg     string
+		wantRepo    string
+		wantErr     bool
+	}{
+		{target: commandTarget{}, defaultRepo: "tools", wantOrg: "acme", wantRepo: "tools"},
+		{target: commandTarget{Repository: "backend"}, defaultRepo: "tools", wantOrg: "acme", wantRepo: "backend"},
+		{target: commandTarget{Organization: "acme"}, defaultRepo: "other/tools", wantOrg: "acme", wantRepo: "tools"},
+		{target: commandTarget{Organization: "acme", Repository: "other/backend"}, wantErr: true},
+		{target: commandTarget{Organization: "ACME", Repository: "acme/backend"}, wantOrg: "ACME", wantRepo: "backend"},
+		{target: commandTarget{}, defaultRepo: "other/tools", wantErr: true},
+		{target: commandTarget{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Setenv("GITHUB_REPO", tt.defaultRepo)
+		githubAct, err := tt.target.resolve("C1", "U1")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("resolve(%+v) with GITHUB_REPO %q, error %v, want error %v", tt.target, tt.defaultRepo, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (githubAct.Organization != tt.wantOrg || githubAct.Repository != tt.wantRepo) {
+			t.Errorf("resolve(%+v) with GITHUB_REPO %q = %s/%s, want %s/%s", tt.target, tt.defaultRepo, githubAct.Organization, githubAct.Repository, tt.wantOrg, tt.wantRepo)
+		}
+	}
+}
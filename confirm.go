@@ -0,0 +1,254 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/shomali11/slacker"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+	"strings"
+	"sync"
+	"time"
+)
+
+// confirmationTTL is how long a confirmation button stays valid
+const confirmationTTL = 15 * time.Minute
+
+// maxSectionText is the text limit of a Slack section block
+const maxSectionText = 3000
+
+// confirmation is an action that runs once the requester, or one of the
+// approvers, clicks Confirm.
+type confirmation struct {
+	// Requester is the Slack user who asked for the action and the only one
+	// who may confirm it, unless Approvers is set
+	Requester string
+	// Approvers are the Slack users who decide instead of the Requester
+	Approvers []string
+	// Summary describes the action in the logs
+	Summary string
+	// ConfirmLabel and CancelLabel name the buttons, Confirm and Cancel by
+	// default
+	ConfirmLabel string
+	CancelLabel  string
+	// TTL is how long the buttons stay valid, confirmationTTL by default
+	TTL time.Duration
+	// Run performs the action and returns the message posted as the result
+	Run func(confirmedBy string) string
+	// OnCancel, when set, runs on Cancel and returns the message posted as
+	// the result
+	OnCancel func(cancelledBy string) string
+	expires  time.Time
+	// messages are the posted messages with the buttons, all of them are
+	// updated with the outcome
+	messages []postedMessage
+}
+
+// postedMessage is a Slack message by channel and timestamp.
+type postedMessage struct {
+	Channel   string
+	Timestamp string
+}
+
+// mayDecide reports whether the Slack user may click the buttons.
+func (c *confirmation) mayDecide(user string) bool {
+	if len(c.Approvers) == 0 {
+		return c.Requester == user
+	}
+	return contains(c.Approvers, user)
+}
+
+// deciders names the Slack users who may click the buttons.
+func (c *confirmation) deciders() string {
+	if len(c.Approvers) == 0 {
+		return fmt.Sprintf("<@%s>", c.Requester)
+	}
+	var users []string
+	for _, user := range c.Approvers {
+		users = append(users, fmt.Sprintf("<@%s>", user))
+	}
+	return strings.Join(users, ", ")
+}
+
+var (
+	confirmationsMu sync.Mutex
+	confirmations   = make(map[string]*confirmation)
+)
+
+// registerConfirmation keeps c under a new id until one of its buttons is
+// clicked or it expires.
+func registerConfirmation(c *confirmation) (string, error) {
+	id, err := newConfirmationID()
+	if err != nil {
+		return "", err
+	}
+	ttl := c.TTL
+	if ttl == 0 {
+		ttl = confirmationTTL
+	}
+	c.expires = time.Now().Add(ttl)
+	confirmationsMu.Lock()
+	for key, pending := range confirmations {
+		if time.Now().After(pending.expires) {
+			delete(confirmations, key)
+		}
+	}
+	confirmations[id] = c
+	confirmationsMu.Unlock()
+	return id, nil
+}
+
+func forgetConfirmation(id string) {
+	confirmationsMu.Lock()
+	delete(confirmations, id)
+	confirmationsMu.Unlock()
+}
+
+// postConfirmation posts text with the details and the buttons of the
+// confirmation id to the channel.
+func postConfirmation(client *slack.Client, channel string, id string, text string, details []string, c *confirmation) error {
+	confirmLabel, cancelLabel := c.ConfirmLabel, c.CancelLabel
+	// Confirm is for destructive actions, a named action is not
+	confirmStyle := slack.StylePrimary
+	if len(confirmLabel) == 0 {
+		confirmLabel = "Confirm"
+		confirmStyle = slack.StyleDanger
+	}
+	if len(cancelLabel) == 0 {
+		cancelLabel = "Cancel"
+	}
+	body := text
+	if len(details) > 0 {
+		body = body + "\n" + strings.Join(details, "")
+	}
+	if len(body) > maxSectionText {
+		body = body[:maxSectionText-20] + "\n_...and more_"
+	}
+	postedChannel, timestamp, err := client.PostMessage(channel,
+		slack.MsgOptionText(text, false),
+		slack.MsgOptionBlocks(
+			slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, body, false, false), nil, nil),
+			slack.NewActionBlock("confirmation",
+				slack.NewButtonBlockElement("confirm", id, slack.NewTextBlockObject(slack.PlainTextType, confirmLabel, false, false)).WithStyle(confirmStyle),
+				slack.NewButtonBlockElement("cancel", id, slack.NewTextBlockObject(slack.PlainTextType, cancelLabel, false, false)),
+			),
+		),
+	)
+	if err != nil {
+		return err
+	}
+	confirmationsMu.Lock()
+	c.messages = append(c.messages, postedMessage{Channel: postedChannel, Timestamp: timestamp})
+	confirmationsMu.Unlock()
+	return nil
+}
+
+// askConfirmation posts text with the details and Confirm/Cancel buttons to
+// the channel and keeps c until one of them is clicked or it expires.
+func askConfirmation(client *slack.Client, channel string, text string, details []string, c *confirmation) error {
+	id, err := registerConfirmation(c)
+	if err != nil {
+		return err
+	}
+	if err := postConfirmation(client, channel, id, text, details, c); err != nil {
+		forgetConfirmation(id)
+		return fmt.Errorf("unable to ask for confirmation, Error: %s", err)
+	}
+	return nil
+}
+
+// askApproval sends text with the details and the buttons to every approver
+// of c by direct message, the first of them to click decides. It returns how
+// many approvers were reached.
+func askApproval(client *slack.Client, text string, details []string, c *confirmation) (int, error) {
+	id, err := registerConfirmation(c)
+	if err != nil {
+		return 0, err
+	}
+	reached := 0
+	for _, approver := range c.Approvers {
+		if err := postConfirmation(client, approver, id, text, details, c); err != nil {
+			log.Info().Msg(fmt.Sprintf("Unable to ask %s to approve the %s, Error: %s", approver, c.Summary, err))
+			continue
+		}
+		reached++
+	}
+	if reached == 0 {
+		forgetConfirmation(id)
+		return 0, fmt.Errorf("unable to reach any approver")
+	}
+	return reached, nil
+}
+
+// handleInteraction runs or cancels the confirmation behind a clicked button.
+func handleInteraction(s *slacker.Slacker, evt *socketmode.Event, callback *slack.InteractionCallback) {
+	s.SocketMode().Ack(*evt.Request)
+	if callback.Type != slack.InteractionTypeBlockActions {
+		return
+	}
+	for _, action := range callback.ActionCallback.BlockActions {
+		if action.ActionID != "confirm" && action.ActionID != "cancel" {
+			continue
+		}
+		user := callback.User.ID
+		confirmationsMu.Lock()
+		c, ok := confirmations[action.Value]
+		if ok && c.mayDecide(user) {
+			delete(confirmations, action.Value)
+		}
+		messages := []postedMessage{{Channel: callback.Channel.ID, Timestamp: callback.Message.Timestamp}}
+		if ok && c.mayDecide(user) {
+			messages = c.messages
+		}
+		confirmationsMu.Unlock()
+		var result string
+		switch {
+		case !ok || time.Now().After(c.expires):
+			result = "this confirmation has expired, please run the command again"
+		case !c.mayDecide(user):
+			postEphemeral(s.Client(), callback.Channel.ID, user, fmt.Sprintf("only %s can confirm this", c.deciders()))
+			continue
+		case action.ActionID == "cancel":
+			log.Info().Msg(fmt.Sprintf("%s cancelled by %s", c.Summary, user))
+			result = fmt.Sprintf("cancelled by <@%s>", user)
+			if c.OnCancel != nil {
+				result = fmt.Sprintf("%s\n%s", result, c.OnCancel(user))
+			}
+		default:
+			log.Info().Msg(fmt.Sprintf("%s confirmed by %s", c.Summary, user))
+			result = fmt.Sprintf("confirmed by <@%s>\n%s", user, c.Run(user))
+		}
+		// replace the buttons with the outcome, on every message of the
+		// confirmation
+		text := fmt.Sprintf("%s\n%s", callback.Message.Text, result)
+		if len(text) > maxSectionText {
+			text = text[:maxSectionText]
+		}
+		for _, message := range messages {
+			_, _, _, err := s.Client().UpdateMessage(message.Channel, message.Timestamp,
+				slack.MsgOptionText(text, false),
+				slack.MsgOptionBlocks(slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil)),
+			)
+			if err != nil {
+				log.Info().Msg(fmt.Sprintf("Unable to update the confirmation message, Error: %s", err))
+			}
+		}
+	}
+}
+
+func postEphemeral(client *slack.Client, channel string, user string, text string) {
+	_, err := client.PostEphemeral(channel, user, slack.MsgOptionText(text, false))
+	if err != nil {
+		log.Info().Msg(fmt.Sprintf("Unable to send the slack message, Error: %s", err))
+	}
+}
+
+func newConfirmationID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("unable to create a confirmation id, Error: %s", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"github.com/rs/zerolog/log"
+	"github.com/slack-go/slack"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultStaleIssueDays = 30
+
+// staleDigestConfig reads the stale issue digest settings from the
+// environment. The digest is disabled when STALE_DIGEST_SCHEDULE is not set.
+func staleDigestConfig() (*cronSchedule, string, int, error) {
+	spec := os.Getenv("STALE_DIGEST_SCHEDULE")
+	if len(spec) == 0 {
+		return nil, "", 0, nil
+	}
+	schedule, err := parseCronSchedule(spec)
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("the environment variable STALE_DIGEST_SCHEDULE is invalid: %s", err)
+	}
+	channel := os.Getenv("STALE_DIGEST_CHANNEL")
+	if len(channel) == 0 {
+		return nil, "", 0, fmt.Errorf("the environment variable STALE_DIGEST_CHANNEL must be set when STALE_DIGEST_SCHEDULE is set")
+	}
+	days := defaultStaleIssueDays
+	if len(os.Getenv("STALE_DIGEST_DAYS")) > 0 {
+		days, err = strconv.Atoi(os.Getenv("STALE_DIGEST_DAYS"))
+		if err != nil || days <= 0 {
+			return nil, "", 0, fmt.Errorf("the environment variable STALE_DIGEST_DAYS must be a positive number of days")
+		}
+	}
+	return schedule, channel, days, nil
+}
+
+// startStaleIssueDigest posts the stale issue digest to the configured
+// channel on the configured schedule until ctx is done.
+func startStaleIssueDigest(ctx context.Context, client *slack.Client) {
+	schedule, channel, days, err := staleDigestConfig()
+	if err != nil {
+		log.Info().Msg(err.Error())
+		return
+	}
+	if schedule == nil {
+		return
+	}
+	go runOnSchedule(ctx, "stale issue digest", schedule, func() {
+		// the digest covers the default repository of the channel
+		githubAct, err := commandTarget{}.resolve(channel, "")
+		if err != nil {
+			log.Info().Msg(fmt.Sprintf("Unable to build the stale issue digest, Error: %s", err))
+			return
+		}
+		msg, digest, err := githubAct.staleIssueDigest(days)
+		if err != nil {
+			log.Info().Msg(fmt.Sprintf("Unable to build the stale issue digest, Error: %s", err))
+			return
+		}
+		if err := postWithList(client, channel, msg, digest); err != nil {
+			log.Info().Msg(fmt.Sprintf("Unable to post the stale issue digest to %s, Error: %s", channel, err))
+		}
+	})
+}
+
+// staleIssueDigest lists the open issues not updated for the given number of
+// days, grouped by assignee and by label.
+func (g GithubActions) staleIssueDigest(days int) (string, []string, error) {
+	var digest []string
+	since := time.Now().AddDate(0, 0, -days).Format("2006-01-02")
+	if !isDateValue(since) {
+		return "", nil, fmt.Errorf("invalid date %s", since)
+	}
+	g.Issue = &IssueAction{
+		Action:      "list",
+		State:       "open",
+		LastUpdated: since,
+	}
+	issues, err := g.fetchIssues()
+	if err != nil {
+		return "", nil, err
+	}
+	if len(issues) == 0 {
+		return fmt.Sprintf("No issues in `%s/%s` without updates for %d days :tada:", g.Organization, g.Repository, days), nil, nil
+	}
+	byAssignee := make(map[string][]*github.Issue)
+	byLabel := make(map[string][]*github.Issue)
+	for _, issue := range issues {
+		if len(issue.Assignees) == 0 {
+			byAssignee["unassigned"] = append(byAssignee["unassigned"], issue)
+		}
+		for _, assignee := range issue.Assignees {
+			byAssignee[assignee.GetLogin()] = append(byAssignee[assignee.GetLogin()], issue)
+		}
+		if len(issue.Labels) == 0 {
+			byLabel["no label"] = append(byLabel["no label"], issue)
+		}
+		for _, label := range issue.Labels {
+			byLabel[label.GetName()] = append(byLabel[label.GetName()], issue)
+		}
+	}
+	digest = append(digest, "*By assignee*\n")
+	digest = append(digest, digestGroups(byAssignee)...)
+	digest = append(digest, "*By label*\n")
+	digest = append(digest, digestGroups(byLabel)...)
+	return fmt.Sprintf("*%d issue/s in `%s/%s` have not been updated since %s*", len(issues), g.Organization, g.Repository, since), digest, nil
+}
+
+func digestGroups(groups map[string][]*github.Issue) []string {
+	var lines []string
+	var names []string
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("`%s` (%d)\n", name, len(groups[name])))
+		for _, issue := range groups[name] {
+			idle := int(time.Since(issue.GetUpdatedAt()).Hours() / 24)
+			lines = append(lines, fmt.Sprintf("\t• *<%s|#%d>* %s, idle %d days\n", issue.GetHTMLURL(), issue.GetNumber(), strings.TrimSpace(issue.GetTitle()), idle))
+		}
+	}
+	return lines
+}
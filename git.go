@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -17,6 +18,10 @@ import (
 type LabelAction struct {
 	IssueNumber int
 	Action      string
+	Labels      []string
+	Name        string
+	Color       string
+	Description string
 }
 type IssueAction struct {
 	Number      int
@@ -25,39 +30,149 @@ type IssueAction struct {
 	UserName    string
 	LastUpdated string
 	Labels      []string
+	Title       string
+	Body        string
+	Reason      string
+	Assignees   []string
+	// Requester is the Slack user who asked and RequesterName how they
+	// are named in the text posted for them
+	Requester     string
+	RequesterName string
+}
+type PullAction struct {
+	Number    int
+	Action    string
+	State     string
+	Author    string
+	Labels    []string
+	Reviewer  string
+	Method    string
+	Requester string
+}
+type WorkflowAction struct {
+	Action string
+	File   string
+	Ref    string
+	Inputs map[string]interface{}
+	RunID  int64
+}
+type CIAction struct {
+	Action     string
+	Branch     string
+	PullNumber int
+	RunID      int64
+	FailedOnly bool
 }
 type MemberAction struct {
 	UserName string
 	Action   string
 	Team     string
+	FromOrg  bool
+	Role     string
+}
+type InviteAction struct {
+	Action  string
+	Invitee string
+	Team    string
+}
+type CollaboratorAction struct {
+	Action     string
+	Login      string
+	Permission string
+}
+
+type ReportAction struct {
+	Type string
+	Days int
 }
+
 type TeamAction struct {
-	Action string
+	Action      string
+	Slug        string
+	Description string
+	Privacy     string
+	Parent      string
+	Maintainers []string
+	Permission  string
+	Reason      string
 }
 type GithubActions struct {
 	Organization string
 	Repository   string
 	Member       *MemberAction
 	Team         *TeamAction
+	Invite       *InviteAction
+	Collaborator *CollaboratorAction
+	Report       *ReportAction
+	Issue        *IssueAction
+	Label        *LabelAction
+	Pull         *PullAction
+	Workflow     *WorkflowAction
+	CI           *CIAction
 }
 
-var supportedTeamActions = []string{"list"}
-var supportedMemberActions = []string{"get", "add"}
-var supportedMemberOptions = []string{"team"}
+var supportedTeamActions = []string{"list", "members", "show", "create", "delete", "repos", "grant", "revoke", "join"}
+var supportedTeamMutations = []string{"create", "delete", "grant", "revoke", "join"}
+var supportedTeamJoinOptions = []string{"reason"}
+var supportedTeamGrantOptions = []string{"permission"}
+var supportedTeamCreateOptions = []string{"description", "privacy", "parent", "maintainers"}
+var supportedTeamPrivacy = []string{"closed", "secret"}
+var supportedMemberActions = []string{"get", "add", "remove", "role", "import"}
+var supportedMemberOptions = []string{"team", "org", "role"}
+var supportedTeamRoles = []string{"member", "maintainer"}
+var supportedInviteActions = []string{"list", "cancel", "resend", "email"}
+var supportedInviteOptions = []string{"team"}
+var supportedCollaboratorActions = []string{"list", "add", "remove", "audit"}
+var supportedCollaboratorOptions = []string{"permission"}
+var supportedReportTypes = []string{"inactive", "2fa"}
+var supportedReportOptions = []string{"days"}
+var supportedIssueActions = []string{"list", "create", "comment", "close", "reopen", "assign", "unassign"}
+var supportedIssueStates = []string{"open", "closed", "assigned", "unassigned", "assignedto"}
+var supportedIssueOptions = []string{"username", "label", "noupdatesince"}
+var supportedIssueCreateOptions = []string{"title", "body", "labels", "assignees"}
+var supportedIssueCloseOptions = []string{"reason"}
+var supportedIssueAssignOptions = []string{"users"}
+var supportedIssueCloseReasons = []string{"completed", "not_planned"}
+var supportedLabelActions = []string{"add", "remove", "list", "create"}
+var supportedLabelOptions = []string{"labels"}
+var supportedLabelCreateOptions = []string{"name", "color", "description"}
+var supportedPullActions = []string{"list", "show", "reviews", "merge"}
+var supportedPullOptions = []string{"state", "author", "label", "reviewer"}
+var supportedPullStates = []string{"open", "closed", "all"}
+var supportedPullMergeOptions = []string{"method"}
+var supportedPullMergeMethods = []string{"merge", "squash", "rebase"}
+var supportedWorkflowActions = []string{"list", "run", "status"}
+var supportedWorkflowOptions = []string{"ref"}
+var supportedCIActions = []string{"failures", "rerun"}
+var supportedCIRerunOptions = []string{"failed-only"}
 var ExcludeTeamName = []string{"legacy-team", "admin"}
 
-//var availableStates = []string{"open", "closed", "assigned", "unassigned"}
-
-func getGitClient() (*github.Client, context.Context, error) {
+// getGitClient returns a client for the organization, using its token and
+// enterprise URL from BOT_CONFIG_FILE when it is listed there and
+// GITHUB_OAUTH_TOKEN/GITHUB_ENTERPRISE_URL otherwise.
+func getGitClient(organization string) (*github.Client, context.Context, error) {
 	// Initilizing git client
 	ctx := context.Background()
+	token := os.Getenv("GITHUB_OAUTH_TOKEN")
+	baseURL := os.Getenv("GITHUB_ENTERPRISE_URL")
+	orgConf, err := getOrgConfig(organization)
+	if err != nil {
+		return nil, nil, err
+	}
+	if orgConf != nil {
+		token = os.Getenv(orgConf.TokenEnv)
+		baseURL = orgConf.EnterpriseURL
+	}
+	if len(token) == 0 {
+		return nil, nil, fmt.Errorf("no GitHub token configured for the organization `%s`", organization)
+	}
 	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: os.Getenv("GITHUB_OAUTH_TOKEN")},
+		&oauth2.Token{AccessToken: token},
 	)
 	tc := oauth2.NewClient(ctx, ts)
 	client := github.NewClient(tc)
-	if len(os.Getenv("GITHUB_ENTERPRISE_URL")) > 0 {
-		enterpriseURL, err := url.Parse(os.Getenv("GITHUB_ENTERPRISE_URL"))
+	if len(baseURL) > 0 {
+		enterpriseURL, err := url.Parse(baseURL)
 		if err != nil {
 			return nil, nil, fmt.Errorf("unable update new github client custom URL, Error: %s", err)
 		}
@@ -70,7 +185,7 @@ func validateUser(userName string, organization string) (bool, string, error) {
 
 	var err error
 	var reason string
-	client, ctx, err := getGitClient()
+	client, ctx, err := getGitClient(organization)
 	if err != nil {
 		return false, "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
 	} else {
@@ -95,7 +210,7 @@ func (g GithubActions) userGet() (bool, string, error) {
 
 	var err error
 	var reason string
-	client, ctx, err := getGitClient()
+	client, ctx, err := getGitClient(g.Organization)
 	if err != nil {
 		return false, "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
 	}
@@ -107,11 +222,16 @@ func (g GithubActions) userGet() (bool, string, error) {
 	if res.StatusCode != 200 {
 		return false, "Unknown User", fmt.Errorf("unable to find user. Error: %s", err)
 	}
-	return true, fmt.Sprintf("Login:\t*<%s|%s>*\n \nName:\t*`%s`*\nEmail:\t*`%s`*\nID:\t*`%d`*\nPublic Repos:\t*`%d`*\n", *user.HTMLURL, *user.Login, *user.Name, *user.Email, *user.ID, *user.PublicRepos), nil
+	details := fmt.Sprintf("Login:\t*<%s|%s>*\n \nName:\t*`%s`*\nEmail:\t*`%s`*\nID:\t*`%d`*\nPublic Repos:\t*`%d`*\n", user.GetHTMLURL(), user.GetLogin(), orNone(user.GetName()), orNone(user.GetEmail()), user.GetID(), user.GetPublicRepos())
+	membership, err := g.memberDetails()
+	if err != nil {
+		return false, "Internal Error", err
+	}
+	return true, details + membership, nil
 }
 
 func (g GithubActions) validateTeam() (bool, string, error) {
-	client, ctx, err := getGitClient()
+	client, ctx, err := getGitClient(g.Organization)
 	if err != nil {
 		log.Info().Msg(fmt.Sprintf("Unable update New github client, Error: %s", err))
 		return false, "Internal Error", err
@@ -144,6 +264,12 @@ func (g GithubActions) actOnMember() (bool, string, error) {
 			return false, msg, err
 		}
 		return true, msg, nil
+	case g.Member.Action == "role":
+		msg, err := g.changeMemberRole()
+		if err != nil {
+			return false, msg, err
+		}
+		return true, msg, nil
 	case g.Member.Action == "get":
 		_, msg, err := g.userGet()
 		if err != nil {
@@ -163,7 +289,7 @@ func ListTeams(Org string) ([]string, string, error) {
 		Page:    1,
 		PerPage: 100,
 	}
-	client, ctx, err := getGitClient()
+	client, ctx, err := getGitClient(Org)
 	if err != nil {
 		return nil, "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
 	}
@@ -194,9 +320,9 @@ func (g GithubActions) actOnTeam() (bool, []string, string, error) {
 	var teamList []string
 	var message string
 	var err error
-	stat, message, err := g.validateRepoAndOrg()
+	stat, message, err := g.validateOrg()
 	if !stat {
-		return false, teamList, message, fmt.Errorf("invalid org/repo. Error: %s", err)
+		return false, teamList, message, fmt.Errorf("invalid org. Error: %s", err)
 	}
 	switch {
 	case g.Team.Action == "list":
@@ -206,11 +332,69 @@ func (g GithubActions) actOnTeam() (bool, []string, string, error) {
 		}
 		teamList, message, err = ListTeams(g.Organization)
 		return true, teamList, message, nil
+	case g.Team.Action == "create":
+		stat, err = g.validateInputs()
+		if !stat {
+			return false, teamList, "Unknown Options", fmt.Errorf("unknown inputs, Error:%s", err)
+		}
+		message, err = g.createTeam()
+		if err != nil {
+			return false, teamList, message, err
+		}
+		return true, teamList, message, nil
+	case g.Team.Action == "grant" || g.Team.Action == "revoke":
+		stat, message, err = g.validateRepoAndOrg()
+		if !stat {
+			return false, teamList, message, fmt.Errorf("invalid org/repo. Error: %s", err)
+		}
+		stat, err = g.validateInputs()
+		if !stat {
+			return false, teamList, "Unknown Options", fmt.Errorf("unknown inputs, Error:%s", err)
+		}
+		if g.Team.Action == "grant" {
+			message, err = g.grantTeamRepo()
+		} else {
+			message, err = g.revokeTeamRepo()
+		}
+		if err != nil {
+			return false, teamList, message, err
+		}
+		return true, teamList, message, nil
+	case g.Team.Action == "members" || g.Team.Action == "show" || g.Team.Action == "repos":
+		stat, err = g.validateInputs()
+		if !stat {
+			return false, teamList, "Unknown Options", fmt.Errorf("unknown inputs, Error:%s", err)
+		}
+		switch g.Team.Action {
+		case "members":
+			teamList, message, err = g.teamMembers()
+		case "repos":
+			teamList, message, err = g.listTeamRepos()
+		default:
+			teamList, message, err = g.showTeam()
+		}
+		if err != nil {
+			return false, teamList, message, err
+		}
+		return true, teamList, message, nil
 
 	default:
 		return false, teamList, "", fmt.Errorf("unknown Action")
 	}
-	return true, teamList, "", nil
+}
+
+// isProtectedTeam reports whether the bot must not change the team, the
+// admin team and ExcludeTeamName.
+func isProtectedTeam(team string) bool {
+	if strings.EqualFold(team, "admin") {
+		return true
+	}
+	for _, excluded := range ExcludeTeamName {
+		if strings.EqualFold(team, excluded) {
+			return true
+		}
+	}
+	return false
 }
 
 func (g GithubActions) addMember() (bool, string, error) {
@@ -225,12 +409,16 @@ func (g GithubActions) addMember() (bool, string, error) {
 		if err != nil {
 			return false, "", fmt.Errorf("user `%s` failed to add to the team `%s`. Error: %s", g.Member.UserName, g.Member.Team, err)
 		}
-		if teamStatus == "User Added" {
+		if teamStatus == "User Added" && len(g.Member.Role) > 0 {
+			return true, fmt.Sprintf("user `%s` added to team `%s` as `%s`", g.Member.UserName, g.Member.Team, g.Member.Role), nil
+		} else if teamStatus == "User Added" {
 			return true, fmt.Sprintf("user `%s` added to team `%s` ", g.Member.UserName, g.Member.Team), nil
+		} else if teamStatus == "Already A Member" && len(g.Member.Role) > 0 {
+			return true, fmt.Sprintf("user `%s` is already a member of team `%s`, msg me `member role %s team=%s;role=%s` to change their role", g.Member.UserName, g.Member.Team, g.Member.UserName, g.Member.Team, g.Member.Role), nil
 		} else if teamStatus == "Already A Member" {
 			return true, fmt.Sprintf("user `%s` is already a member of team `%s`", g.Member.UserName, g.Member.Team), nil
 		} else {
-			return false, "", fmt.Errorf("user `%`s failed to add to the team `%s`. Erro: %s", g.Member.UserName, g.Member.Team, err)
+			return false, "", fmt.Errorf("user `%s` failed to add to the team `%s`. Erro: %s", g.Member.UserName, g.Member.Team, err)
 		}
 	} else {
 		return false, "", fmt.Errorf("user `%s` failed to add to the team `%s`. Error: %s", g.Member.UserName, g.Member.Team, err)
@@ -246,7 +434,7 @@ func (g GithubActions) OrganizationsEditOrgMembership() (string, error) {
 		}
 		if stat {
 			log.Debug().Msg(fmt.Sprintf("User %s is a valid user", g.Member.UserName))
-			client, ctx, err := getGitClient()
+			client, ctx, err := getGitClient(g.Organization)
 			if err != nil {
 				return "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
 			}
@@ -284,16 +472,20 @@ func (g GithubActions) TeamsAddTeamMembershipBySlug() (string, error) {
 		}
 		if stat {
 			log.Debug().Msg(fmt.Sprintf("User %s is a valid user", g.Member.UserName))
-			client, ctx, err := getGitClient()
+			client, ctx, err := getGitClient(g.Organization)
 			if err != nil {
 				return "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
 			}
-			_, resp, err := client.Teams.AddTeamMembershipBySlug(ctx, g.Organization, g.Member.Team, g.Member.UserName, nil)
+			var opts *github.TeamAddTeamMembershipOptions
+			if len(g.Member.Role) > 0 {
+				opts = &github.TeamAddTeamMembershipOptions{Role: g.Member.Role}
+			}
+			_, resp, err := client.Teams.AddTeamMembershipBySlug(ctx, g.Organization, g.Member.Team, g.Member.UserName, opts)
 			if err != nil {
 				return "Failed to Add", fmt.Errorf("unable to add the user %s. Error: %s", g.Member.UserName, err)
 			}
 			if resp.StatusCode != 200 {
-				fmt.Errorf("unable to add user %s to the Team %s", g.Member.UserName, g.Member.Team)
+				log.Info().Msg(fmt.Sprintf("unable to add user %s to the Team %s", g.Member.UserName, g.Member.Team))
 				status = "Failed to Add"
 			} else {
 				status = "User Added"
@@ -312,7 +504,7 @@ func (g GithubActions) TeamsAddTeamMembershipBySlug() (string, error) {
 
 func (g GithubActions) checkIfUserAlreadyMemberOfOrg() (bool, error) {
 	//Organization Membership
-	client, ctx, err := getGitClient()
+	client, ctx, err := getGitClient(g.Organization)
 	if err != nil {
 		log.Info().Msg(fmt.Sprintf("Unable update New github client, Error: %s", err))
 	} else {
@@ -331,7 +523,7 @@ func (g GithubActions) checkIfUserAlreadyMemberOfOrg() (bool, error) {
 }
 
 func (g GithubActions) checkIfUserAlreadyMemberOfTeam() (bool, error) {
-	client, ctx, err := getGitClient()
+	client, ctx, err := getGitClient(g.Organization)
 	if err != nil {
 		log.Info().Msg(fmt.Sprintf("Unable update New github client, Error: %s", err))
 	} else {
@@ -356,49 +548,58 @@ func (g GithubActions) checkIfUserAlreadyMemberOfTeam() (bool, error) {
 }
 
 func (g GithubActions) validateRepoAndOrg() (bool, string, error) {
-	lstopt := &github.ListOptions{
-		Page:    1,
-		PerPage: 100,
-	}
-	opts := &github.RepositoryListByOrgOptions{ListOptions: *lstopt}
 	var message string
-	var err error
-	client, ctx, err := getGitClient()
+	client, ctx, err := getGitClient(g.Organization)
 	if err != nil {
 		log.Info().Msg(fmt.Sprintf("Unable update New github client, Error: %s", err))
 		return false, "Internal Error", err
 	}
-	for {
-		repoList, resp, err := client.Repositories.ListByOrg(ctx, g.Organization, opts)
-		if err != nil {
+	repo, resp, err := client.Repositories.Get(ctx, g.Organization, g.Repository)
+	if err != nil {
+		if resp != nil && resp.StatusCode == 404 {
+			message = fmt.Sprintf("Unknown Organization:`%s` and/or Repository: `%s`", g.Organization, g.Repository)
+		} else {
 			message = fmt.Sprintf("Unable to get details of Organization:`%s` and/or Repository: `%s`", g.Organization, g.Repository)
-			log.Info().Msg(message)
-			return false, message, err
-		}
-		log.Debug().Msg(fmt.Sprintf("Page numer: %d", opts.ListOptions.Page))
-		if len(repoList) > 0 {
-			for _, repo := range repoList {
-				if *repo.Name == g.Repository && repo.Owner.GetLogin() == g.Organization {
-					message = fmt.Sprintf("Organization: %s and the repository is %s\n", repo.Owner.GetLogin(), *repo.Name)
-					log.Info().Msg(message)
-					return true, message, nil
-				}
-				message = fmt.Sprintf("Unknown Organization:`%s`and/or the repository is `%s`", repo.Owner.GetLogin(), *repo.Name)
-			}
 		}
-		log.Debug().Msg(fmt.Sprintf("Page numer: %d", resp.NextPage))
-		if resp.NextPage == 0 {
-			break
+		log.Info().Msg(message)
+		return false, message, err
+	}
+	message = fmt.Sprintf("Organization: %s and the repository is %s\n", repo.GetOwner().GetLogin(), repo.GetName())
+	log.Info().Msg(message)
+	return true, message, nil
+}
+
+func (g GithubActions) validateOrg() (bool, string, error) {
+	var message string
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		log.Info().Msg(fmt.Sprintf("Unable update New github client, Error: %s", err))
+		return false, "Internal Error", err
+	}
+	_, resp, err := client.Organizations.Get(ctx, g.Organization)
+	if err != nil {
+		if resp != nil && resp.StatusCode == 404 {
+			message = fmt.Sprintf("Unknown Organization:`%s`", g.Organization)
+		} else {
+			message = fmt.Sprintf("Unable to get details of Organization:`%s`", g.Organization)
 		}
-		opts.ListOptions.Page = resp.NextPage
+		log.Info().Msg(message)
+		return false, message, err
 	}
-	return false, message, err
+	return true, fmt.Sprintf("Organization: %s", g.Organization), nil
 }
 
 func isDateValue(stringDate string) bool {
 	_, err := time.Parse("2006-01-02", stringDate)
 	return err == nil
 }
+func isColorValue(color string) bool {
+	if len(color) != 6 {
+		return false
+	}
+	_, err := strconv.ParseUint(color, 16, 32)
+	return err == nil
+}
 func prettyPrint(i interface{}) string {
 	var userDetails string
 	var x map[string]interface{}
@@ -421,6 +622,145 @@ func (g GithubActions) validateInputs() (bool, error) {
 		if len(g.Member.Team) == 0 && g.Member.Action == "add" {
 			return false, fmt.Errorf("`%s` expects team name as input", g.Member.Action)
 		}
+		if g.Member.Action == "remove" && len(g.Member.Team) == 0 && !g.Member.FromOrg {
+			return false, fmt.Errorf("`%s` expects team=<team> or org=true as input", g.Member.Action)
+		}
+		if g.Member.Action == "remove" && len(g.Member.Team) > 0 && g.Member.FromOrg {
+			return false, fmt.Errorf("`%s` expects either team=<team> or org=true, not both", g.Member.Action)
+		}
+		if g.Member.FromOrg && g.Member.Action != "remove" {
+			return false, fmt.Errorf("org=true is only supported by `remove`")
+		}
+		if g.Member.Action == "role" && (len(g.Member.Team) == 0 || len(g.Member.Role) == 0) {
+			return false, fmt.Errorf("`%s` expects team=<team> and role=<role> as input", g.Member.Action)
+		}
+		if len(g.Member.Role) > 0 && !contains(supportedTeamRoles, g.Member.Role) {
+			return false, fmt.Errorf("role must be one of %s", strings.Join(codeSlice(supportedTeamRoles), ", "))
+		}
+		if len(g.Member.Role) > 0 && g.Member.Action != "add" && g.Member.Action != "role" {
+			return false, fmt.Errorf("role is only supported by `add` and `role`")
+		}
+	}
+	if g.Team != nil {
+		if g.Team.Action != "list" && len(g.Team.Slug) == 0 {
+			return false, fmt.Errorf("`%s` expects a team as input", g.Team.Action)
+		}
+		// list, show, members and repos only read the team
+		if contains(supportedTeamMutations, g.Team.Action) && isProtectedTeam(g.Team.Slug) {
+			return false, fmt.Errorf("You are not privileged to update `%s` team", g.Team.Slug)
+		}
+		if len(g.Team.Parent) > 0 && isProtectedTeam(g.Team.Parent) {
+			return false, fmt.Errorf("You are not privileged to update `%s` team", g.Team.Parent)
+		}
+		if g.Team.Action == "join" && len(g.Team.Reason) == 0 {
+			return false, fmt.Errorf("`join` expects reason=<why you need to join> as input")
+		}
+		if (g.Team.Action == "grant" || g.Team.Action == "revoke") && len(g.Repository) == 0 {
+			return false, fmt.Errorf("`%s` expects repo=<repository> as input", g.Team.Action)
+		}
+		if g.Team.Action == "grant" && !contains(repoPermissions, g.Team.Permission) {
+			return false, fmt.Errorf("permission must be one of %s", strings.Join(codeSlice(repoPermissions), ", "))
+		}
+		if len(g.Team.Privacy) > 0 && !contains(supportedTeamPrivacy, g.Team.Privacy) {
+			return false, fmt.Errorf("privacy must be one of %s", strings.Join(codeSlice(supportedTeamPrivacy), ", "))
+		}
+	}
+	if g.Invite != nil {
+		if g.Invite.Action != "list" && len(g.Invite.Invitee) == 0 {
+			return false, fmt.Errorf("`%s` expects a login, an email address or an invitation id as input", g.Invite.Action)
+		}
+		if g.Invite.Action == "email" && !isEmailValue(g.Invite.Invitee) {
+			return false, fmt.Errorf("`%s` is not a valid email address", g.Invite.Invitee)
+		}
+		if len(g.Invite.Team) > 0 && g.Invite.Action != "email" {
+			return false, fmt.Errorf("team is only supported by `email`")
+		}
+		if isProtectedTeam(g.Invite.Team) {
+			return false, fmt.Errorf("You are not privileged to update `%s` team", g.Invite.Team)
+		}
+	}
+	if g.Issue != nil {
+		if g.Issue.Action != "list" && g.Issue.Action != "create" && g.Issue.Number == 0 {
+			return false, fmt.Errorf("`%s` expects an issue number as input", g.Issue.Action)
+		}
+		if g.Issue.Action == "create" && len(g.Issue.Title) == 0 {
+			return false, fmt.Errorf("`%s` expects title as input", g.Issue.Action)
+		}
+		if g.Issue.Action == "comment" && len(g.Issue.Body) == 0 {
+			return false, fmt.Errorf("`%s` expects the comment text as input", g.Issue.Action)
+		}
+		if (g.Issue.Action == "assign" || g.Issue.Action == "unassign") && len(g.Issue.Assignees) == 0 {
+			return false, fmt.Errorf("`%s` expects users as input", g.Issue.Action)
+		}
+		if len(g.Issue.Reason) > 0 && !contains(supportedIssueCloseReasons, g.Issue.Reason) {
+			return false, fmt.Errorf("reason must be one of %s", strings.Join(codeSlice(supportedIssueCloseReasons), ", "))
+		}
+		if g.Issue.State == "assignedto" && len(g.Issue.UserName) == 0 {
+			return false, fmt.Errorf("`%s` expects username as input", g.Issue.State)
+		}
+		if len(g.Issue.LastUpdated) > 0 && !isDateValue(g.Issue.LastUpdated) {
+			return false, fmt.Errorf("noupdatesince must be in yyyy-mm-dd format")
+		}
+	}
+	if g.Pull != nil {
+		if g.Pull.Action != "list" && g.Pull.Number == 0 {
+			return false, fmt.Errorf("`%s` expects a pull request number as input", g.Pull.Action)
+		}
+		if len(g.Pull.State) > 0 && !contains(supportedPullStates, g.Pull.State) {
+			return false, fmt.Errorf("state must be one of %s", strings.Join(codeSlice(supportedPullStates), ", "))
+		}
+		if len(g.Pull.Method) > 0 && !contains(supportedPullMergeMethods, g.Pull.Method) {
+			return false, fmt.Errorf("method must be one of %s", strings.Join(codeSlice(supportedPullMergeMethods), ", "))
+		}
+	}
+	if g.Workflow != nil {
+		if g.Workflow.Action == "run" && len(g.Workflow.File) == 0 {
+			return false, fmt.Errorf("`%s` expects a workflow file name as input", g.Workflow.Action)
+		}
+		if g.Workflow.Action == "status" && g.Workflow.RunID == 0 {
+			return false, fmt.Errorf("`%s` expects a run id as input", g.Workflow.Action)
+		}
+		if len(g.Workflow.Inputs) > 10 {
+			return false, fmt.Errorf("a workflow accepts at most 10 inputs")
+		}
+	}
+	if g.Collaborator != nil {
+		if (g.Collaborator.Action == "add" || g.Collaborator.Action == "remove") && len(g.Collaborator.Login) == 0 {
+			return false, fmt.Errorf("`%s` expects a login as input", g.Collaborator.Action)
+		}
+		if g.Collaborator.Action == "add" && !contains(repoPermissions, g.Collaborator.Permission) {
+			return false, fmt.Errorf("permission must be one of %s", strings.Join(codeSlice(repoPermissions), ", "))
+		}
+		if len(g.Collaborator.Permission) > 0 && g.Collaborator.Action != "add" {
+			return false, fmt.Errorf("permission is only supported by `add`")
+		}
+	}
+	if g.Report != nil {
+		if g.Report.Type == "inactive" && (g.Report.Days < 1 || g.Report.Days > maxInactiveDays) {
+			return false, fmt.Errorf("days must be between 1 and %d", maxInactiveDays)
+		}
+	}
+	if g.CI != nil {
+		if g.CI.Action == "failures" && len(g.CI.Branch) == 0 && g.CI.PullNumber == 0 {
+			return false, fmt.Errorf("`%s` expects a branch or a pull request number as input", g.CI.Action)
+		}
+		if g.CI.Action == "rerun" && g.CI.RunID == 0 {
+			return false, fmt.Errorf("`%s` expects a run id as input", g.CI.Action)
+		}
+	}
+	if g.Label != nil {
+		if (g.Label.Action == "add" || g.Label.Action == "remove") && g.Label.IssueNumber == 0 {
+			return false, fmt.Errorf("`%s` expects an issue number as input", g.Label.Action)
+		}
+		if (g.Label.Action == "add" || g.Label.Action == "remove") && len(g.Label.Labels) == 0 {
+			return false, fmt.Errorf("`%s` expects labels as input", g.Label.Action)
+		}
+		if g.Label.Action == "create" && len(g.Label.Name) == 0 {
+			return false, fmt.Errorf("`%s` expects name as input", g.Label.Action)
+		}
+		if len(g.Label.Color) > 0 && !isColorValue(g.Label.Color) {
+			return false, fmt.Errorf("color must be a 6 digit hex value like `d73a4a`")
+		}
 	}
 	return true, nil
 }
@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestValidateTeamInputs(t *testing.T) {
+	tests := []struct {
+		team    TeamAction
+		wantErr bool
+	}{
+		{team: TeamAction{Action: "list"}},
+		{team: TeamAction{Action: "show", Slug: "admin"}},
+		{team: TeamAction{Action: "members", Slug: "Admin"}},
+		{team: TeamAction{Action: "repos", Slug: "admin"}},
+		{team: TeamAction{Action: "show"}, wantErr: true},
+		{team: TeamAction{Action: "delete", Slug: "admin"}, wantErr: true},
+		{team: TeamAction{Action: "grant", Slug: "admin", Permission: "push"}, wantErr: true},
+		{team: TeamAction{Action: "grant", Slug: "storage", Permission: "push"}},
+		{team: TeamAction{Action: "revoke", Slug: "admin"}, wantErr: true},
+		{team: TeamAction{Action: "join", Slug: "admin", Reason: "on call"}, wantErr: true},
+		{team: TeamAction{Action: "create", Slug: "admin"}, wantErr: true},
+		{team: TeamAction{Action: "create", Slug: "storage", Parent: "admin"}, wantErr: true},
+		{team: TeamAction{Action: "delete", Slug: "storage"}},
+	}
+	for _, tt := range tests {
+		team := tt.team
+		stat, err := GithubActions{Repository: "backend", Team: &team}.validateInputs()
+		if stat == tt.wantErr {
+			t.Errorf("validateInputs() with %+v = %t, %v, want error %t", tt.team, stat, err, tt.wantErr)
+		}
+	}
+}
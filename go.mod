@@ -4,8 +4,10 @@ go 1.18
 
 require (
 	github.com/google/go-github/v45 v45.1.0
+	github.com/rs/zerolog v1.27.0
 	github.com/shomali11/slacker v0.0.0-20220129203130-6c28a41fb7b0
 	github.com/slack-go/slack v0.9.1
+	golang.org/x/oauth2 v0.0.0-20220608161450-d0670ef3b1eb
 )
 
 require (
@@ -18,12 +20,10 @@ require (
 	github.com/mattn/go-colorable v0.1.12 // indirect
 	github.com/mattn/go-isatty v0.0.14 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
-	github.com/rs/zerolog v1.27.0 // indirect
 	github.com/shomali11/commander v0.0.0-20191122162317-51bc574c29ba // indirect
 	github.com/shomali11/proper v0.0.0-20180607004733-233a9a872c30 // indirect
 	golang.org/x/crypto v0.0.0-20210817164053-32db794688a5 // indirect
 	golang.org/x/net v0.0.0-20220127200216-cd36cc0744dd // indirect
-	golang.org/x/oauth2 v0.0.0-20220608161450-d0670ef3b1eb // indirect
 	golang.org/x/sys v0.0.0-20211216021012-1d35b9e2eb4e // indirect
 	google.golang.org/appengine v1.6.7 // indirect
 	google.golang.org/protobuf v1.25.0 // indirect
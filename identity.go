@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"github.com/rs/zerolog/log"
+	"github.com/slack-go/slack"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultIdentityFile is where the linked GitHub logins are kept when
+// IDENTITY_FILE is not set
+const defaultIdentityFile = "identities.json"
+
+// linkCodeTTL is how long the code of a `link` stays valid
+const linkCodeTTL = 30 * time.Minute
+
+// identity is the GitHub login a Slack user proved to own.
+type identity struct {
+	Login    string    `json:"login"`
+	LinkedAt time.Time `json:"linked_at"`
+}
+
+// pendingLink is a `link` waiting for its code to show up on GitHub.
+type pendingLink struct {
+	Login   string
+	Code    string
+	Created time.Time
+}
+
+var (
+	identitiesMu     sync.Mutex
+	identities       map[string]identity
+	pendingLinks     = make(map[string]pendingLink)
+	identitiesLoaded bool
+)
+
+func identityFile() string {
+	if len(os.Getenv("IDENTITY_FILE")) > 0 {
+		return os.Getenv("IDENTITY_FILE")
+	}
+	return defaultIdentityFile
+}
+
+// loadIdentities reads the identity file the first time it is needed, a
+// missing file is an empty store. identitiesMu must be held.
+func loadIdentities() error {
+	if identitiesLoaded {
+		return nil
+	}
+	identities = make(map[string]identity)
+	content, err := ioutil.ReadFile(identityFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			identitiesLoaded = true
+			return nil
+		}
+		return fmt.Errorf("unable to read the identity file `%s`. Error: %s", identityFile(), err)
+	}
+	if err := json.Unmarshal(content, &identities); err != nil {
+		return fmt.Errorf("unable to parse the identity file `%s`. Error: %s", identityFile(), err)
+	}
+	identitiesLoaded = true
+	return nil
+}
+
+// saveIdentities writes the store. identitiesMu must be held.
+func saveIdentities() error {
+	if err := writeJSONFile(identityFile(), identities); err != nil {
+		return fmt.Errorf("unable to save the identity file `%s`. Error: %s", identityFile(), err)
+	}
+	return nil
+}
+
+// writeJSONFile writes v to path through a temporary file so a crash never
+// leaves it half written.
+func writeJSONFile(path string, v interface{}) error {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// linkedLogin returns the GitHub login linked to the Slack user, or an empty
+// string.
+func linkedLogin(slackUser string) (string, error) {
+	identitiesMu.Lock()
+	defer identitiesMu.Unlock()
+	if err := loadIdentities(); err != nil {
+		return "", err
+	}
+	return identities[slackUser].Login, nil
+}
+
+// linkedIdentities returns a copy of the store, by Slack user.
+func linkedIdentities() (map[string]identity, error) {
+	identitiesMu.Lock()
+	defer identitiesMu.Unlock()
+	if err := loadIdentities(); err != nil {
+		return nil, err
+	}
+	linked := make(map[string]identity, len(identities))
+	for slackUser, id := range identities {
+		linked[slackUser] = id
+	}
+	return linked, nil
+}
+
+// linkedSlackUser returns the Slack user linked to the GitHub login, or an
+// empty string.
+func linkedSlackUser(login string) (string, error) {
+	identitiesMu.Lock()
+	defer identitiesMu.Unlock()
+	if err := loadIdentities(); err != nil {
+		return "", err
+	}
+	for slackUser, linked := range identities {
+		if strings.EqualFold(linked.Login, login) {
+			return slackUser, nil
+		}
+	}
+	return "", nil
+}
+
+// resolveLogin replaces `me` with the GitHub login of the requester, any
+// other value is returned as it is.
+func resolveLogin(value string, slackUser string) (string, error) {
+	if !strings.EqualFold(strings.TrimSpace(value), "me") {
+		return value, nil
+	}
+	login, err := linkedLogin(slackUser)
+	if err != nil {
+		return "", err
+	}
+	if len(login) == 0 {
+		return "", fmt.Errorf("you have not linked your GitHub account yet, msg me `link <github login>` first")
+	}
+	return login, nil
+}
+
+// resolveLogins is resolveLogin for a list of logins.
+func resolveLogins(values []string, slackUser string) ([]string, error) {
+	var logins []string
+	for _, value := range values {
+		login, err := resolveLogin(value, slackUser)
+		if err != nil {
+			return nil, err
+		}
+		logins = append(logins, login)
+	}
+	return logins, nil
+}
+
+// linkAccount runs `link`: the first call gives the Slack user a code to put
+// in the bio or a public gist of the GitHub account, the next call checks it
+// is there and stores the link.
+func linkAccount(slackUser string, login string) (string, error) {
+	client, ctx, err := getGitClient(os.Getenv("GITHUB_ORG"))
+	if err != nil {
+		return "", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	user, _, err := client.Users.Get(ctx, login)
+	if err != nil {
+		return "", fmt.Errorf("unable to find user `%s`. Error: %s", login, err)
+	}
+	login = user.GetLogin()
+	owner, err := linkedSlackUser(login)
+	if err != nil {
+		return "", err
+	}
+	if len(owner) > 0 && owner != slackUser {
+		return "", fmt.Errorf("`%s` is already linked to another Slack user, they must `unlink` it first", login)
+	}
+
+	identitiesMu.Lock()
+	pending, ok := pendingLinks[slackUser]
+	if !ok || pending.Login != login || time.Since(pending.Created) > linkCodeTTL {
+		id, err := newConfirmationID()
+		if err != nil {
+			identitiesMu.Unlock()
+			return "", err
+		}
+		pending = pendingLink{Login: login, Code: fmt.Sprintf("slack-link-%s", id[:16]), Created: time.Now()}
+		pendingLinks[slackUser] = pending
+		identitiesMu.Unlock()
+		return fmt.Sprintf("To prove you own `%s`, add `%s` to your GitHub profile bio or to a new public gist within %d minutes, then msg me `link %s` again. You can remove it once linked.", login, pending.Code, int(linkCodeTTL.Minutes()), login), nil
+	}
+	identitiesMu.Unlock()
+
+	found := strings.Contains(user.GetBio(), pending.Code)
+	if !found {
+		found, err = gistContains(ctx, client, login, pending)
+		if err != nil {
+			return "", err
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("`%s` is not in the profile bio or a public gist of `%s` yet, msg me `link %s` again once it is", pending.Code, login, login)
+	}
+
+	identitiesMu.Lock()
+	defer identitiesMu.Unlock()
+	if err := loadIdentities(); err != nil {
+		return "", err
+	}
+	previous, hadPrevious := identities[slackUser]
+	identities[slackUser] = identity{Login: login, LinkedAt: time.Now()}
+	if err := saveIdentities(); err != nil {
+		if hadPrevious {
+			identities[slackUser] = previous
+		} else {
+			delete(identities, slackUser)
+		}
+		return "", err
+	}
+	delete(pendingLinks, slackUser)
+	log.Info().Msg(fmt.Sprintf("Slack user %s linked to the GitHub login %s", slackUser, login))
+	return fmt.Sprintf("your Slack account is now linked to `%s`", login), nil
+}
+
+// gistContains checks the public gists created since the link started for
+// its code, in the description or the content of a file.
+func gistContains(ctx context.Context, client *github.Client, login string, pending pendingLink) (bool, error) {
+	gists, _, err := client.Gists.List(ctx, login, &github.GistListOptions{Since: pending.Created.Add(-time.Minute)})
+	if err != nil {
+		return false, fmt.Errorf("unable to list the gists of `%s`. Error: %s", login, err)
+	}
+	for _, gist := range gists {
+		if strings.Contains(gist.GetDescription(), pending.Code) {
+			return true, nil
+		}
+		full, _, err := client.Gists.Get(ctx, gist.GetID())
+		if err != nil {
+			return false, fmt.Errorf("unable to get the gist `%s` of `%s`. Error: %s", gist.GetID(), login, err)
+		}
+		for _, file := range full.Files {
+			if strings.Contains(file.GetContent(), pending.Code) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func unlinkAccount(slackUser string) (string, error) {
+	identitiesMu.Lock()
+	defer identitiesMu.Unlock()
+	if err := loadIdentities(); err != nil {
+		return "", err
+	}
+	linked, ok := identities[slackUser]
+	if !ok {
+		return "your Slack account is not linked to a GitHub account", nil
+	}
+	delete(identities, slackUser)
+	if err := saveIdentities(); err != nil {
+		identities[slackUser] = linked
+		return "", err
+	}
+	log.Info().Msg(fmt.Sprintf("Slack user %s unlinked from the GitHub login %s", slackUser, linked.Login))
+	return fmt.Sprintf("your Slack account is no longer linked to `%s`", linked.Login), nil
+}
+
+func whoami(slackUser string) (string, error) {
+	identitiesMu.Lock()
+	defer identitiesMu.Unlock()
+	if err := loadIdentities(); err != nil {
+		return "", err
+	}
+	linked, ok := identities[slackUser]
+	if !ok {
+		return "your Slack account is not linked to a GitHub account, msg me `link <github login>` to link it", nil
+	}
+	return fmt.Sprintf("you are `%s` on GitHub, linked %s", linked.Login, age(linked.LinkedAt)), nil
+}
+
+// slackUserForLogin returns the Slack user ID of a GitHub login: the Slack
+// user who linked it, or else the Slack user with the public email address
+// of the GitHub account. It is empty when neither is known.
+func slackUserForLogin(client *slack.Client, organization string, login string) (string, error) {
+	slackUser, err := linkedSlackUser(login)
+	if err != nil || len(slackUser) > 0 {
+		return slackUser, err
+	}
+	githubClient, ctx, err := getGitClient(organization)
+	if err != nil {
+		return "", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	user, _, err := githubClient.Users.Get(ctx, login)
+	if err != nil {
+		return "", fmt.Errorf("unable to find user `%s`. Error: %s", login, err)
+	}
+	if len(user.GetEmail()) == 0 {
+		return "", nil
+	}
+	found, err := client.GetUserByEmail(user.GetEmail())
+	if err != nil {
+		if err.Error() == "users_not_found" {
+			return "", nil
+		}
+		return "", fmt.Errorf("unable to look up the Slack user of `%s`, Error: %s", login, err)
+	}
+	return found.ID, nil
+}
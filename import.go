@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/shomali11/slacker"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"io"
+	"strings"
+)
+
+// maxImportRows limits the rows of a `member import` file, every row costs
+// several GitHub API calls
+const maxImportRows = 200
+
+// maxImportFileSize limits the size of a `member import` file
+const maxImportFileSize = 1 << 20
+
+// memberImportRow is one `github_login,team,role` row of a `member import`
+// file and what happened to it.
+type memberImportRow struct {
+	Line   int
+	Login  string
+	Team   string
+	Role   string
+	Valid  bool
+	Result string
+}
+
+// importFile downloads the CSV file shared with the command message.
+func importFile(client *slack.Client, event interface{}) ([]byte, error) {
+	message, ok := event.(*slackevents.MessageEvent)
+	if !ok || len(message.Files) == 0 {
+		return nil, fmt.Errorf("upload a CSV file with `github_login,team,role` rows and write `member import` as its message")
+	}
+	if len(message.Files) > 1 {
+		return nil, fmt.Errorf("upload a single CSV file")
+	}
+	file := message.Files[0]
+	if file.Filetype != "csv" && !strings.HasSuffix(strings.ToLower(file.Name), ".csv") {
+		return nil, fmt.Errorf("`%s` is not a CSV file", file.Name)
+	}
+	if file.Size > maxImportFileSize {
+		return nil, fmt.Errorf("`%s` is larger than %d bytes", file.Name, maxImportFileSize)
+	}
+	var content bytes.Buffer
+	if err := client.GetFile(file.URLPrivateDownload, &content); err != nil {
+		return nil, fmt.Errorf("unable to download `%s`, Error: %s", file.Name, err)
+	}
+	return content.Bytes(), nil
+}
+
+// parseMemberImport reads the rows of the file, the header row is optional
+// and an empty role is a plain member.
+func parseMemberImport(content []byte) ([]*memberImportRow, error) {
+	var rows []*memberImportRow
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	for i := 0; ; i++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the CSV file, Error: %s", err)
+		}
+		if i == 0 && len(record) > 0 && strings.EqualFold(strings.TrimSpace(record[0]), "github_login") {
+			continue
+		}
+		if len(record) == 1 && len(strings.TrimSpace(record[0])) == 0 {
+			continue
+		}
+		// the reader skips empty lines, so the record index is not the line
+		line, _ := reader.FieldPos(0)
+		row := &memberImportRow{Line: line}
+		if len(record) < 2 || len(record) > 3 {
+			row.Result = "expected `github_login,team,role`"
+			rows = append(rows, row)
+			continue
+		}
+		row.Login = strings.TrimSpace(record[0])
+		row.Team = strings.TrimSpace(record[1])
+		row.Role = "member"
+		if len(record) == 3 && len(strings.TrimSpace(record[2])) > 0 {
+			row.Role = strings.ToLower(strings.TrimSpace(record[2]))
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("the CSV file has no rows")
+	}
+	if len(rows) > maxImportRows {
+		return nil, fmt.Errorf("the CSV file has %d rows, split it into files of at most %d rows", len(rows), maxImportRows)
+	}
+	return rows, nil
+}
+
+// planMemberImport validates every row with validateUser and validateTeam
+// and describes what applying it would do.
+func (g GithubActions) planMemberImport(rows []*memberImportRow) (string, []string) {
+	var plan []string
+	valid := 0
+	for _, row := range rows {
+		row.Valid = false
+		if len(row.Result) == 0 {
+			row.Result = g.planMemberImportRow(row)
+		}
+		if row.Valid {
+			valid++
+			plan = append(plan, fmt.Sprintf(":white_check_mark: line %d `%s` → `%s` as `%s`: %s\n", row.Line, row.Login, row.Team, row.Role, row.Result))
+		} else {
+			plan = append(plan, fmt.Sprintf(":x: line %d `%s` → `%s`: %s\n", row.Line, row.Login, row.Team, row.Result))
+		}
+	}
+	return fmt.Sprintf("Dry run of `member import` in `%s`: %d of %d row/s will be applied, invalid rows are skipped.", g.Organization, valid, len(rows)), plan
+}
+
+func (g GithubActions) planMemberImportRow(row *memberImportRow) string {
+	if isProtectedTeam(row.Team) {
+		return fmt.Sprintf("You are not privileged to update `%s` team", row.Team)
+	}
+	if !contains(supportedTeamRoles, row.Role) {
+		return fmt.Sprintf("role must be one of %s", strings.Join(codeSlice(supportedTeamRoles), ", "))
+	}
+	if stat, reason, _ := validateUser(row.Login, g.Organization); !stat {
+		return reason
+	}
+	rowAct := g
+	rowAct.Member = &MemberAction{UserName: row.Login, Action: "add", Team: row.Team, Role: row.Role}
+	if stat, reason, _ := rowAct.validateTeam(); !stat {
+		return reason
+	}
+	row.Valid = true
+	if stat, _ := rowAct.checkIfUserAlreadyMemberOfTeam(); stat {
+		return "already a member, skipped"
+	}
+	if stat, _ := rowAct.checkIfUserAlreadyMemberOfOrg(); !stat {
+		return "will be invited to the organization and added"
+	}
+	return "will be added"
+}
+
+// applyMemberImport runs `member add` for every valid row.
+func (g GithubActions) applyMemberImport(rows []*memberImportRow) string {
+	added, failed := 0, 0
+	for _, row := range rows {
+		if !row.Valid {
+			continue
+		}
+		rowAct := g
+		rowAct.Member = &MemberAction{UserName: row.Login, Action: "add", Team: row.Team, Role: row.Role}
+		_, msg, err := rowAct.addMember()
+		if err != nil {
+			failed++
+			row.Result = err.Error()
+			continue
+		}
+		added++
+		row.Result = msg
+	}
+	return fmt.Sprintf("`member import` applied: %d row/s done, %d failed, %d skipped as invalid", added, failed, len(rows)-added-failed)
+}
+
+// memberImportResults is the per-row result CSV of an import.
+func memberImportResults(rows []*memberImportRow) (string, error) {
+	var content bytes.Buffer
+	writer := csv.NewWriter(&content)
+	writer.Write([]string{"line", "github_login", "team", "role", "valid", "result"})
+	for _, row := range rows {
+		writer.Write([]string{fmt.Sprintf("%d", row.Line), row.Login, row.Team, row.Role, fmt.Sprintf("%t", row.Valid), strings.ReplaceAll(row.Result, "`", "")})
+	}
+	writer.Flush()
+	return content.String(), writer.Error()
+}
+
+// importMembers runs `member import`: it posts the dry run of the uploaded
+// file and applies it once the requester confirms.
+func importMembers(botCtx slacker.BotContext, repoTarget *commandTarget, response slacker.ResponseWriter) {
+	channel := botCtx.Event().Channel
+	githubAct, err := repoTarget.resolveOrg()
+	if err != nil {
+		response.Reply(err.Error())
+		return
+	}
+	content, err := importFile(botCtx.Client(), botCtx.Event().Data)
+	if err != nil {
+		response.Reply(err.Error())
+		return
+	}
+	rows, err := parseMemberImport(content)
+	if err != nil {
+		response.Reply(err.Error())
+		return
+	}
+	response.Reply(fmt.Sprintf("checking %d row/s, this may take a while", len(rows)))
+	msg, plan := githubAct.planMemberImport(rows)
+	// the plan can be longer than a confirmation message
+	replyWithList(response, msg, plan)
+	err = askConfirmation(botCtx.Client(), channel, "Apply the `member import` dry run above?", nil, &confirmation{
+		Requester: botCtx.Event().User,
+		Summary:   fmt.Sprintf("member import of %d row/s into %s", len(rows), githubAct.Organization),
+		Run: func(confirmedBy string) string {
+			msg := githubAct.applyMemberImport(rows)
+			results, err := memberImportResults(rows)
+			if err == nil {
+				err = uploadCSV(botCtx.Client(), channel, "member-import-results.csv", msg, results)
+			}
+			if err != nil {
+				log.Info().Msg(fmt.Sprintf("Unable to upload the member import results, Error: %s", err))
+			}
+			return msg
+		},
+	})
+	if err != nil {
+		response.Reply(err.Error())
+	}
+}
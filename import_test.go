@@ -0,0 +1,80 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMemberImport(t *testing.T) {
+	tests := []struct {
+		content string
+		want    []memberImportRow
+		wantErr bool
+	}{
+		{
+			content: "github_login,team,role\njohns,storage,maintainer\n",
+			want:    []memberImportRow{{Line: 2, Login: "johns", Team: "storage", Role: "maintainer"}},
+		},
+		{
+			content: "johns, storage\nmaryk,network,Maintainer\n",
+			want: []memberImportRow{
+				{Line: 1, Login: "johns", Team: "storage", Role: "member"},
+				{Line: 2, Login: "maryk", Team: "network", Role: "maintainer"},
+			},
+		},
+		{
+			content: "johns,storage,\n\nmaryk\n",
+			want: []memberImportRow{
+				{Line: 1, Login: "johns", Team: "storage", Role: "member"},
+				{Line: 3, Result: "expected `github_login,team,role`"},
+			},
+		},
+		{content: "github_login,team,role\n", wantErr: true},
+		{content: "", wantErr: true},
+		{content: "johns,\"storage\n", wantErr: true},
+		{content: strings.Repeat("johns,storage\n", maxImportRows+1), wantErr: true},
+	}
+	for _, tt := range tests {
+		rows, err := parseMemberImport([]byte(tt.content))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseMemberImport(%q) error %v, want error %v", tt.content, err, tt.wantErr)
+			continue
+		}
+		var got []memberImportRow
+		for _, row := range rows {
+			got = append(got, *row)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseMemberImport(%q) = %+v, want %+v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestMemberImportResults(t *testing.T) {
+	tests := []struct {
+		rows []*memberImportRow
+		want string
+	}{
+		{rows: nil, want: "line,github_login,team,role,valid,result\n"},
+		{
+			rows: []*memberImportRow{
+				{Line: 2, Login: "johns", Team: "storage", Role: "member", Valid: true, Result: "added to `storage`"},
+				{Line: 3, Result: "expected `github_login,team,role`"},
+			},
+			want: "line,github_login,team,role,valid,result\n" +
+				"2,johns,storage,member,true,added to storage\n" +
+				"3,,,,false,\"expected github_login,team,role\"\n",
+		},
+	}
+	for _, tt := range tests {
+		got, err := memberImportResults(tt.rows)
+		if err != nil {
+			t.Errorf("memberImportResults(%+v) error %v", tt.rows, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("memberImportResults(%+v) = %q, want %q", tt.rows, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"github.com/rs/zerolog/log"
+	"net/mail"
+	"strconv"
+	"strings"
+)
+
+func (g GithubActions) actOnInvite() (bool, []string, string, error) {
+	var inviteList []string
+	stat, message, err := g.validateOrg()
+	if !stat {
+		return false, inviteList, message, fmt.Errorf("invalid org. Error: %s", err)
+	}
+	stat, err = g.validateInputs()
+	if !stat {
+		return false, inviteList, "Unknown Options", fmt.Errorf("unknown inputs, Error:%s", err)
+	}
+	switch {
+	case g.Invite.Action == "list":
+		inviteList, message, err = g.listInvitations()
+	case g.Invite.Action == "cancel":
+		message, err = g.cancelInvitation()
+	case g.Invite.Action == "resend":
+		message, err = g.resendInvitation()
+	case g.Invite.Action == "email":
+		message, err = g.inviteEmail()
+	default:
+		return false, inviteList, "", fmt.Errorf("unknown Action")
+	}
+	if err != nil {
+		return false, inviteList, message, err
+	}
+	return true, inviteList, message, nil
+}
+
+func (g GithubActions) pendingInvitations() ([]*github.Invitation, error) {
+	var invitations []*github.Invitation
+	lstopt := &github.ListOptions{
+		Page:    1,
+		PerPage: 100,
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	for {
+		pending, resp, err := client.Organizations.ListPendingOrgInvitations(ctx, g.Organization, lstopt)
+		if err != nil {
+			return nil, fmt.Errorf("getting pending invitations failed, Error: %s", err)
+		}
+		invitations = append(invitations, pending...)
+		if resp.NextPage == 0 {
+			break
+		}
+		lstopt.Page = resp.NextPage
+	}
+	return invitations, nil
+}
+
+func (g GithubActions) invitationTeams(invitation *github.Invitation) ([]*github.Team, error) {
+	var teams []*github.Team
+	if invitation.GetTeamCount() == 0 {
+		return teams, nil
+	}
+	lstopt := &github.ListOptions{
+		Page:    1,
+		PerPage: 100,
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	for {
+		page, resp, err := client.Organizations.ListOrgInvitationTeams(ctx, g.Organization, strconv.FormatInt(invitation.GetID(), 10), lstopt)
+		if err != nil {
+			return nil, fmt.Errorf("getting the teams of invitation `%d` failed, Error: %s", invitation.GetID(), err)
+		}
+		teams = append(teams, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		lstopt.Page = resp.NextPage
+	}
+	return teams, nil
+}
+
+func (g GithubActions) listInvitations() ([]string, string, error) {
+	var inviteList []string
+	invitations, err := g.pendingInvitations()
+	if err != nil {
+		return nil, "Internal Error", err
+	}
+	for _, invitation := range invitations {
+		teams, err := g.invitationTeams(invitation)
+		if err != nil {
+			return nil, "Internal Error", err
+		}
+		var slugs []string
+		for _, team := range teams {
+			slugs = append(slugs, team.GetSlug())
+		}
+		inviteList = append(inviteList, fmt.Sprintf("`%d`\t*%s*\t`%s` invited by `%s` %s, teams: %s\n", invitation.GetID(), invitee(invitation), invitation.GetRole(), invitation.GetInviter().GetLogin(), age(invitation.GetCreatedAt()), orNone(strings.Join(slugs, ", "))))
+	}
+	if len(inviteList) == 0 {
+		return inviteList, fmt.Sprintf("No pending invitations in `%s`", g.Organization), nil
+	}
+	return inviteList, fmt.Sprintf("%d pending invitation/s in `%s`", len(inviteList), g.Organization), nil
+}
+
+// findInvitation returns the pending invitation of a login, an email
+// address or an invitation ID.
+func (g GithubActions) findInvitation() (*github.Invitation, error) {
+	invitations, err := g.pendingInvitations()
+	if err != nil {
+		return nil, err
+	}
+	for _, invitation := range invitations {
+		if strings.EqualFold(invitation.GetLogin(), g.Invite.Invitee) || strings.EqualFold(invitation.GetEmail(), g.Invite.Invitee) || strconv.FormatInt(invitation.GetID(), 10) == g.Invite.Invitee {
+			return invitation, nil
+		}
+	}
+	return nil, fmt.Errorf("no pending invitation for `%s` in `%s`, msg me `invite list` to see them", g.Invite.Invitee, g.Organization)
+}
+
+// deleteInvitation cancels an invitation, go-github has no method for it.
+func (g GithubActions) deleteInvitation(invitation *github.Invitation) error {
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	req, err := client.NewRequest("DELETE", fmt.Sprintf("orgs/%s/invitations/%d", g.Organization, invitation.GetID()), nil)
+	if err != nil {
+		return err
+	}
+	_, err = client.Do(ctx, req, nil)
+	if err != nil {
+		return fmt.Errorf("unable to cancel the invitation of `%s`. Error: %s", invitee(invitation), err)
+	}
+	return nil
+}
+
+func (g GithubActions) cancelInvitation() (string, error) {
+	invitation, err := g.findInvitation()
+	if err != nil {
+		return "Unknown Invitation", err
+	}
+	if err := g.deleteInvitation(invitation); err != nil {
+		return "Failed to Cancel", err
+	}
+	log.Info().Msg(fmt.Sprintf("Invitation %d of %s to the org %s cancelled", invitation.GetID(), invitee(invitation), g.Organization))
+	return fmt.Sprintf("invitation of `%s` to `%s` cancelled", invitee(invitation), g.Organization), nil
+}
+
+// resendInvitation creates the invitation again with the same role and teams
+// and cancels the pending one, GitHub has no API to resend one.
+func (g GithubActions) resendInvitation() (string, error) {
+	invitation, err := g.findInvitation()
+	if err != nil {
+		return "Unknown Invitation", err
+	}
+	teams, err := g.invitationTeams(invitation)
+	if err != nil {
+		return "Internal Error", err
+	}
+	opts := &github.CreateOrgInvitationOptions{
+		Role: github.String(invitation.GetRole()),
+	}
+	for _, team := range teams {
+		opts.TeamID = append(opts.TeamID, team.GetID())
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	if len(invitation.GetLogin()) > 0 {
+		user, _, err := client.Users.Get(ctx, invitation.GetLogin())
+		if err != nil {
+			return "Unknown User", fmt.Errorf("unable to find user `%s`. Error: %s", invitation.GetLogin(), err)
+		}
+		opts.InviteeID = user.ID
+	} else {
+		opts.Email = invitation.Email
+	}
+	// the new invitation is created first so a failure leaves the pending one
+	_, rsp, err := client.Organizations.CreateOrgInvitation(ctx, g.Organization, opts)
+	switch {
+	case err == nil:
+		if err := g.deleteInvitation(invitation); err != nil {
+			return "Failed to Cancel", fmt.Errorf("a new invitation of `%s` was sent but the previous one could not be cancelled, msg me `invite cancel %d` to cancel it. Error: %s", invitee(invitation), invitation.GetID(), err)
+		}
+	case rsp != nil && rsp.StatusCode == 422:
+		// GitHub refuses a second pending invitation, so the pending one has
+		// to be cancelled before it can be sent again
+		if err := g.deleteInvitation(invitation); err != nil {
+			return "Failed to Resend", fmt.Errorf("the invitation of `%s` is unchanged. %s", invitee(invitation), err)
+		}
+		_, _, err = client.Organizations.CreateOrgInvitation(ctx, g.Organization, opts)
+		if err != nil {
+			return "Failed to Resend", fmt.Errorf("the original invitation of `%s` was cancelled and could not be sent again, msg me `member add` or `invite email` to invite them. Error: %s", invitee(invitation), err)
+		}
+	default:
+		return "Failed to Resend", fmt.Errorf("unable to send the invitation of `%s` again, the original invitation is unchanged. Error: %s", invitee(invitation), err)
+	}
+	log.Info().Msg(fmt.Sprintf("Invitation of %s to the org %s resent", invitee(invitation), g.Organization))
+	return fmt.Sprintf("invitation of `%s` to `%s` sent again", invitee(invitation), g.Organization), nil
+}
+
+// inviteEmail invites an email address to the organization and the team.
+func (g GithubActions) inviteEmail() (string, error) {
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	opts := &github.CreateOrgInvitationOptions{
+		Email: github.String(g.Invite.Invitee),
+		Role:  github.String("direct_member"),
+	}
+	if len(g.Invite.Team) > 0 {
+		team, _, err := client.Teams.GetTeamBySlug(ctx, g.Organization, g.Invite.Team)
+		if err != nil {
+			return "Unknown Team", fmt.Errorf("unable to find the team `%s`. Error: %s", g.Invite.Team, err)
+		}
+		opts.TeamID = []int64{team.GetID()}
+	}
+	_, _, err = client.Organizations.CreateOrgInvitation(ctx, g.Organization, opts)
+	if err != nil {
+		return "Failed to Invite", fmt.Errorf("unable to invite `%s` to `%s`. Error: %s", g.Invite.Invitee, g.Organization, err)
+	}
+	if len(g.Invite.Team) > 0 {
+		return fmt.Sprintf("`%s` invited to `%s` and team `%s`", g.Invite.Invitee, g.Organization, g.Invite.Team), nil
+	}
+	return fmt.Sprintf("`%s` invited to `%s`", g.Invite.Invitee, g.Organization), nil
+}
+
+// invitee is the login of the invited user, or the email address for an
+// invitation by email.
+func invitee(invitation *github.Invitation) string {
+	if len(invitation.GetLogin()) > 0 {
+		return invitation.GetLogin()
+	}
+	return invitation.GetEmail()
+}
+
+func isEmailValue(address string) bool {
+	parsed, err := mail.ParseAddress(address)
+	return err == nil && parsed.Address == address
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"github.com/rs/zerolog/log"
+	"strings"
+	"time"
+)
+
+func (g GithubActions) actOnIssue() (bool, []string, string, error) {
+	var issueList []string
+	stat, message, err := g.validateRepoAndOrg()
+	if !stat {
+		return false, issueList, message, fmt.Errorf("invalid org/repo. Error: %s", err)
+	}
+	stat, err = g.validateInputs()
+	if !stat {
+		return false, issueList, "Unknown Options", fmt.Errorf("unknown inputs, Error:%s", err)
+	}
+	switch {
+	case g.Issue.Action == "list":
+		issueList, message, err = g.listIssues()
+	case g.Issue.Action == "create":
+		message, err = g.createIssue()
+	case g.Issue.Action == "comment":
+		message, err = g.commentOnIssue()
+	case g.Issue.Action == "close":
+		message, err = g.closeIssue()
+	case g.Issue.Action == "reopen":
+		message, err = g.reopenIssue()
+	case g.Issue.Action == "assign":
+		message, err = g.assignIssue()
+	case g.Issue.Action == "unassign":
+		message, err = g.unassignIssue()
+	default:
+		return false, issueList, "", fmt.Errorf("unknown Action")
+	}
+	if err != nil {
+		return false, issueList, message, err
+	}
+	return true, issueList, message, nil
+}
+
+func (g GithubActions) listIssues() ([]string, string, error) {
+	var issueList []string
+	issues, err := g.fetchIssues()
+	if err != nil {
+		return nil, "Internal Error", err
+	}
+	for _, issue := range issues {
+		issueList = append(issueList, formatIssue(issue))
+	}
+	if len(issueList) == 0 {
+		return issueList, fmt.Sprintf("No `%s` issues found in `%s/%s`", g.Issue.State, g.Organization, g.Repository), nil
+	}
+	return issueList, fmt.Sprintf("%d issue/s found", len(issueList)), nil
+}
+
+// fetchIssues pages through the repository issues matching the state and
+// filters of g.Issue, leaving out pull requests.
+func (g GithubActions) fetchIssues() ([]*github.Issue, error) {
+	var issueList []*github.Issue
+	var notUpdatedSince time.Time
+	opts := &github.IssueListByRepoOptions{
+		State:     "open",
+		Labels:    g.Issue.Labels,
+		Sort:      "updated",
+		Direction: "asc",
+		ListOptions: github.ListOptions{
+			Page:    1,
+			PerPage: 100,
+		},
+	}
+	switch g.Issue.State {
+	case "closed":
+		opts.State = "closed"
+	case "assigned":
+		opts.Assignee = "*"
+	case "unassigned":
+		opts.Assignee = "none"
+	case "assignedto":
+		opts.Assignee = g.Issue.UserName
+	}
+	if len(g.Issue.UserName) > 0 && g.Issue.State != "unassigned" {
+		opts.Assignee = g.Issue.UserName
+	}
+	if len(g.Issue.LastUpdated) > 0 {
+		notUpdatedSince, _ = time.Parse("2006-01-02", g.Issue.LastUpdated)
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	for {
+		issues, resp, err := client.Issues.ListByRepo(ctx, g.Organization, g.Repository, opts)
+		if err != nil {
+			return nil, fmt.Errorf("getting issues failed, Error: %s", err)
+		}
+		for _, issue := range issues {
+			// The issues API returns pull requests as well
+			if issue.IsPullRequest() {
+				continue
+			}
+			if !notUpdatedSince.IsZero() && !issue.GetUpdatedAt().Before(notUpdatedSince) {
+				continue
+			}
+			issueList = append(issueList, issue)
+		}
+		log.Debug().Msg(fmt.Sprintf("Page numer: %d", resp.NextPage))
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.ListOptions.Page = resp.NextPage
+	}
+	return issueList, nil
+}
+
+func formatIssue(issue *github.Issue) string {
+	var assignees []string
+	for _, assignee := range issue.Assignees {
+		assignees = append(assignees, assignee.GetLogin())
+	}
+	var labels []string
+	for _, label := range issue.Labels {
+		labels = append(labels, label.GetName())
+	}
+	line := fmt.Sprintf("*<%s|#%d>*\t%s\tupdated: `%s`", issue.GetHTMLURL(), issue.GetNumber(), issue.GetTitle(), issue.GetUpdatedAt().Format("2006-01-02"))
+	if len(assignees) > 0 {
+		line = line + fmt.Sprintf("\tassignees: `%s`", strings.Join(assignees, ", "))
+	}
+	if len(labels) > 0 {
+		line = line + fmt.Sprintf("\tlabels: `%s`", strings.Join(labels, ", "))
+	}
+	return line + "\n"
+}
+
+func (g GithubActions) createIssue() (string, error) {
+	var labels []string
+	if len(g.Issue.Labels) > 0 {
+		repoLabels, err := g.repoLabels()
+		if err != nil {
+			return "Internal Error", err
+		}
+		for _, name := range g.Issue.Labels {
+			label, ok := repoLabels[strings.ToLower(name)]
+			if !ok {
+				return "Unknown Label", fmt.Errorf("unknown label `%s`, msg me `label list` for the available labels", name)
+			}
+			labels = append(labels, label.GetName())
+		}
+	}
+	for _, assignee := range g.Issue.Assignees {
+		stat, reas, err := validateUser(assignee, g.Organization)
+		if !stat {
+			return reas, fmt.Errorf("unable to get the user `%s` details, Error: %s", assignee, err)
+		}
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	req := &github.IssueRequest{
+		Title: github.String(g.Issue.Title),
+	}
+	if body := g.attributed(g.Issue.Body); len(body) > 0 {
+		req.Body = github.String(body)
+	}
+	if len(labels) > 0 {
+		req.Labels = &labels
+	}
+	if len(g.Issue.Assignees) > 0 {
+		req.Assignees = &g.Issue.Assignees
+	}
+	issue, _, err := client.Issues.Create(ctx, g.Organization, g.Repository, req)
+	if err != nil {
+		return "Failed to Create", fmt.Errorf("unable to create the issue. Error: %s", err)
+	}
+	log.Info().Msg(fmt.Sprintf("Issue %s/%s#%d created, requested by %s", g.Organization, g.Repository, issue.GetNumber(), g.Issue.Requester))
+	return fmt.Sprintf("issue *<%s|#%d>* created in `%s/%s`", issue.GetHTMLURL(), issue.GetNumber(), g.Organization, g.Repository), nil
+}
+
+func (g GithubActions) commentOnIssue() (string, error) {
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	comment, _, err := client.Issues.CreateComment(ctx, g.Organization, g.Repository, g.Issue.Number, &github.IssueComment{Body: github.String(g.attributed(g.Issue.Body))})
+	if err != nil {
+		return "Failed to Comment", fmt.Errorf("unable to comment on issue `#%d`. Error: %s", g.Issue.Number, err)
+	}
+	log.Info().Msg(fmt.Sprintf("Comment %d added to issue %s/%s#%d, requested by %s", comment.GetID(), g.Organization, g.Repository, g.Issue.Number, g.Issue.Requester))
+	return fmt.Sprintf("*<%s|comment>* added to issue `#%d`", comment.GetHTMLURL(), g.Issue.Number), nil
+}
+
+// attributed appends who asked for the text, which is posted with the token
+// of the bot.
+func (g GithubActions) attributed(body string) string {
+	if len(g.Issue.RequesterName) == 0 {
+		return body
+	}
+	return fmt.Sprintf("%s\n\n_Posted from Slack by %s_", strings.TrimSpace(body), g.Issue.RequesterName)
+}
+
+// issueStateRequest is used to close an issue with a reason, which
+// github.IssueRequest does not support yet.
+type issueStateRequest struct {
+	State       string `json:"state"`
+	StateReason string `json:"state_reason,omitempty"`
+}
+
+func (g GithubActions) closeIssue() (string, error) {
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	reason := g.Issue.Reason
+	if len(reason) == 0 {
+		reason = "completed"
+	}
+	u := fmt.Sprintf("repos/%v/%v/issues/%d", g.Organization, g.Repository, g.Issue.Number)
+	req, err := client.NewRequest("PATCH", u, &issueStateRequest{State: "closed", StateReason: reason})
+	if err != nil {
+		return "Internal Error", fmt.Errorf("unable to close issue `#%d`. Error: %s", g.Issue.Number, err)
+	}
+	issue := new(github.Issue)
+	_, err = client.Do(ctx, req, issue)
+	if err != nil {
+		return "Failed to Close", fmt.Errorf("unable to close issue `#%d`. Error: %s", g.Issue.Number, err)
+	}
+	return fmt.Sprintf("issue *<%s|#%d>* closed as `%s`", issue.GetHTMLURL(), issue.GetNumber(), reason), nil
+}
+
+func (g GithubActions) reopenIssue() (string, error) {
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	issue, _, err := client.Issues.Edit(ctx, g.Organization, g.Repository, g.Issue.Number, &github.IssueRequest{State: github.String("open")})
+	if err != nil {
+		return "Failed to Reopen", fmt.Errorf("unable to reopen issue `#%d`. Error: %s", g.Issue.Number, err)
+	}
+	return fmt.Sprintf("issue *<%s|#%d>* reopened", issue.GetHTMLURL(), issue.GetNumber()), nil
+}
+
+func (g GithubActions) assignIssue() (string, error) {
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	issue, _, err := client.Issues.AddAssignees(ctx, g.Organization, g.Repository, g.Issue.Number, g.Issue.Assignees)
+	if err != nil {
+		return "Failed to Assign", fmt.Errorf("unable to assign issue `#%d`. Error: %s", g.Issue.Number, err)
+	}
+	// GitHub silently ignores users who can not be assigned to the repository
+	var assigned, skipped []string
+	for _, user := range g.Issue.Assignees {
+		if issueHasAssignee(issue, user) {
+			assigned = append(assigned, user)
+		} else {
+			skipped = append(skipped, user)
+		}
+	}
+	msg := fmt.Sprintf("issue *<%s|#%d>* assigned to `%s`", issue.GetHTMLURL(), issue.GetNumber(), strings.Join(assigned, ", "))
+	if len(assigned) == 0 {
+		msg = fmt.Sprintf("issue *<%s|#%d>* was not assigned", issue.GetHTMLURL(), issue.GetNumber())
+	}
+	if len(skipped) > 0 {
+		msg = msg + fmt.Sprintf("\n`%s` could not be assigned, they must have access to `%s/%s`", strings.Join(skipped, ", "), g.Organization, g.Repository)
+	}
+	return msg, nil
+}
+
+func (g GithubActions) unassignIssue() (string, error) {
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	issue, _, err := client.Issues.RemoveAssignees(ctx, g.Organization, g.Repository, g.Issue.Number, g.Issue.Assignees)
+	if err != nil {
+		return "Failed to Unassign", fmt.Errorf("unable to unassign issue `#%d`. Error: %s", g.Issue.Number, err)
+	}
+	return fmt.Sprintf("`%s` unassigned from issue *<%s|#%d>*", strings.Join(g.Issue.Assignees, ", "), issue.GetHTMLURL(), issue.GetNumber()), nil
+}
+
+func issueHasAssignee(issue *github.Issue, user string) bool {
+	for _, assignee := range issue.Assignees {
+		if strings.EqualFold(assignee.GetLogin(), user) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"github.com/rs/zerolog/log"
+	"github.com/shomali11/slacker"
+	"github.com/slack-go/slack"
+	"strings"
+	"time"
+)
+
+// joinRequestTTL is how long the maintainers have to answer a `team join`
+const joinRequestTTL = 7 * 24 * time.Hour
+
+// teamMaintainers returns the logins of the maintainers of the team.
+func (g GithubActions) teamMaintainers() ([]string, error) {
+	var maintainers []string
+	opts := &github.TeamListTeamMembersOptions{
+		Role: "maintainer",
+		ListOptions: github.ListOptions{
+			Page:    1,
+			PerPage: 100,
+		},
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	for {
+		members, resp, err := client.Teams.ListTeamMembersBySlug(ctx, g.Organization, g.Team.Slug, opts)
+		if err != nil {
+			return nil, fmt.Errorf("getting the maintainers of team `%s` failed, Error: %s", g.Team.Slug, err)
+		}
+		for _, member := range members {
+			maintainers = append(maintainers, member.GetLogin())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return maintainers, nil
+}
+
+// requestTeamJoin runs `team join`: it asks the maintainers of the team to
+// approve adding the requester's linked GitHub login, and adds it with
+// addMember once one of them does.
+func requestTeamJoin(botCtx slacker.BotContext, githubAct GithubActions, response slacker.ResponseWriter) {
+	requester := botCtx.Event().User
+	channel := botCtx.Event().Channel
+	login, err := linkedLogin(requester)
+	if err != nil {
+		response.Reply(err.Error())
+		return
+	}
+	if len(login) == 0 {
+		response.Reply("msg me `link <github login>` to link your GitHub account before asking to join a team")
+		return
+	}
+	stat, message, err := githubAct.validateOrg()
+	if !stat {
+		response.Reply(fmt.Sprintf("%s. Error: %s", message, err))
+		return
+	}
+	if stat, err := githubAct.validateInputs(); !stat {
+		response.Reply(err.Error())
+		return
+	}
+	githubAct.Member = &MemberAction{
+		UserName: login,
+		Action:   "add",
+		Team:     githubAct.Team.Slug,
+	}
+	if stat, reason, err := githubAct.validateTeam(); !stat {
+		response.Reply(fmt.Sprintf("%s `%s`. Error: %s", reason, githubAct.Team.Slug, err))
+		return
+	}
+	if stat, _ := githubAct.checkIfUserAlreadyMemberOfTeam(); stat {
+		response.Reply(fmt.Sprintf("`%s` is already a member of team `%s`", login, githubAct.Team.Slug))
+		return
+	}
+	maintainers, err := githubAct.teamMaintainers()
+	if err != nil {
+		response.Reply(err.Error())
+		return
+	}
+	var approvers []string
+	for _, maintainer := range maintainers {
+		slackUser, err := slackUserForLogin(botCtx.Client(), githubAct.Organization, maintainer)
+		if err != nil {
+			log.Info().Msg(err.Error())
+			continue
+		}
+		if len(slackUser) > 0 && slackUser != requester && !contains(approvers, slackUser) {
+			approvers = append(approvers, slackUser)
+		}
+	}
+	if len(approvers) == 0 {
+		response.Reply(fmt.Sprintf("none of the %d maintainer/s of team `%s` has a known Slack user, ask an admin to add you", len(maintainers), githubAct.Team.Slug))
+		return
+	}
+	text := fmt.Sprintf("<@%s> (`%s`) asks to join team `%s` of `%s`:\n>%s", requester, login, githubAct.Team.Slug, githubAct.Organization, githubAct.Team.Reason)
+	reached, err := askApproval(botCtx.Client(), text, nil, &confirmation{
+		Requester:    requester,
+		Approvers:    approvers,
+		Summary:      fmt.Sprintf("request of %s to join team %s of %s", login, githubAct.Team.Slug, githubAct.Organization),
+		ConfirmLabel: "Approve",
+		CancelLabel:  "Deny",
+		TTL:          joinRequestTTL,
+		Run: func(confirmedBy string) string {
+			_, msg, err := githubAct.addMember()
+			if err != nil {
+				msg = err.Error()
+			}
+			notifyUser(botCtx.Client(), channel, fmt.Sprintf("<@%s> approved your request to join team `%s`: %s", confirmedBy, githubAct.Team.Slug, msg))
+			return msg
+		},
+		OnCancel: func(cancelledBy string) string {
+			notifyUser(botCtx.Client(), channel, fmt.Sprintf("<@%s> denied your request to join team `%s`", cancelledBy, githubAct.Team.Slug))
+			return fmt.Sprintf("<@%s> has been told", requester)
+		},
+	})
+	if err != nil {
+		response.Reply(fmt.Sprintf("unable to ask the maintainers of team `%s`, Error: %s", githubAct.Team.Slug, err))
+		return
+	}
+	response.Reply(fmt.Sprintf("asked %d maintainer/s of team `%s` to approve, I will let you know their answer. The request expires in %d days", reached, githubAct.Team.Slug, int(joinRequestTTL.Hours()/24)))
+}
+
+// notifyUser posts text to a channel, or a Slack user by ID, outside of a
+// command reply.
+func notifyUser(client *slack.Client, channel string, text string) {
+	if _, _, err := client.PostMessage(channel, slack.MsgOptionText(strings.TrimSpace(text), false)); err != nil {
+		log.Info().Msg(fmt.Sprintf("Unable to send the slack message, Error: %s", err))
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"sort"
+	"strings"
+)
+
+func (g GithubActions) actOnLabel() (bool, []string, string, error) {
+	var labelList []string
+	stat, message, err := g.validateRepoAndOrg()
+	if !stat {
+		return false, labelList, message, fmt.Errorf("invalid org/repo. Error: %s", err)
+	}
+	stat, err = g.validateInputs()
+	if !stat {
+		return false, labelList, "Unknown Options", fmt.Errorf("unknown inputs, Error:%s", err)
+	}
+	switch {
+	case g.Label.Action == "list":
+		labelList, message, err = g.listLabels()
+	case g.Label.Action == "add":
+		labelList, message, err = g.addLabels()
+	case g.Label.Action == "remove":
+		labelList, message, err = g.removeLabels()
+	case g.Label.Action == "create":
+		message, err = g.createLabel()
+	default:
+		return false, labelList, "", fmt.Errorf("unknown Action")
+	}
+	if err != nil {
+		return false, labelList, message, err
+	}
+	return true, labelList, message, nil
+}
+
+// repoLabels returns the labels defined on the repository keyed by lower
+// case name, as GitHub treats label names case-insensitively.
+func (g GithubActions) repoLabels() (map[string]*github.Label, error) {
+	labels := make(map[string]*github.Label)
+	lstopt := &github.ListOptions{
+		Page:    1,
+		PerPage: 100,
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	for {
+		repoLabels, resp, err := client.Issues.ListLabels(ctx, g.Organization, g.Repository, lstopt)
+		if err != nil {
+			return nil, fmt.Errorf("getting labels failed, Error: %s", err)
+		}
+		for _, label := range repoLabels {
+			labels[strings.ToLower(label.GetName())] = label
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		lstopt.Page = resp.NextPage
+	}
+	return labels, nil
+}
+
+func (g GithubActions) listLabels() ([]string, string, error) {
+	var labelList []string
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	if g.Label.IssueNumber > 0 {
+		labels, _, err := client.Issues.ListLabelsByIssue(ctx, g.Organization, g.Repository, g.Label.IssueNumber, &github.ListOptions{PerPage: 100})
+		if err != nil {
+			return nil, "Internal Error", fmt.Errorf("getting labels of issue `#%d` failed, Error: %s", g.Label.IssueNumber, err)
+		}
+		for _, label := range labels {
+			labelList = append(labelList, formatLabel(label))
+		}
+		return labelList, fmt.Sprintf("%d label/s found on issue `#%d`", len(labelList), g.Label.IssueNumber), nil
+	}
+	labels, err := g.repoLabels()
+	if err != nil {
+		return nil, "Internal Error", err
+	}
+	for _, label := range labels {
+		labelList = append(labelList, formatLabel(label))
+	}
+	sort.Strings(labelList)
+	return labelList, fmt.Sprintf("%d label/s found in `%s/%s`", len(labelList), g.Organization, g.Repository), nil
+}
+
+func (g GithubActions) addLabels() ([]string, string, error) {
+	var results []string
+	labels, err := g.repoLabels()
+	if err != nil {
+		return nil, "Internal Error", err
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	added := 0
+	for _, name := range g.Label.Labels {
+		label, ok := labels[strings.ToLower(name)]
+		if !ok {
+			results = append(results, fmt.Sprintf(":x: `%s`: unknown label\n", name))
+			continue
+		}
+		_, _, err := client.Issues.AddLabelsToIssue(ctx, g.Organization, g.Repository, g.Label.IssueNumber, []string{label.GetName()})
+		if err != nil {
+			results = append(results, fmt.Sprintf(":x: `%s`: %s\n", label.GetName(), err))
+			continue
+		}
+		added++
+		results = append(results, fmt.Sprintf(":white_check_mark: `%s`: added\n", label.GetName()))
+	}
+	return results, fmt.Sprintf("%d of %d label/s added to issue `#%d`", added, len(g.Label.Labels), g.Label.IssueNumber), nil
+}
+
+func (g GithubActions) removeLabels() ([]string, string, error) {
+	var results []string
+	labels, err := g.repoLabels()
+	if err != nil {
+		return nil, "Internal Error", err
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	removed := 0
+	for _, name := range g.Label.Labels {
+		label, ok := labels[strings.ToLower(name)]
+		if !ok {
+			results = append(results, fmt.Sprintf(":x: `%s`: unknown label\n", name))
+			continue
+		}
+		resp, err := client.Issues.RemoveLabelForIssue(ctx, g.Organization, g.Repository, g.Label.IssueNumber, label.GetName())
+		if err != nil {
+			if resp != nil && resp.StatusCode == 404 {
+				results = append(results, fmt.Sprintf(":x: `%s`: not set on the issue\n", label.GetName()))
+			} else {
+				results = append(results, fmt.Sprintf(":x: `%s`: %s\n", label.GetName(), err))
+			}
+			continue
+		}
+		removed++
+		results = append(results, fmt.Sprintf(":white_check_mark: `%s`: removed\n", label.GetName()))
+	}
+	return results, fmt.Sprintf("%d of %d label/s removed from issue `#%d`", removed, len(g.Label.Labels), g.Label.IssueNumber), nil
+}
+
+func (g GithubActions) createLabel() (string, error) {
+	labels, err := g.repoLabels()
+	if err != nil {
+		return "Internal Error", err
+	}
+	if _, ok := labels[strings.ToLower(g.Label.Name)]; ok {
+		return "Label Exists", fmt.Errorf("label `%s` already exists in `%s/%s`", g.Label.Name, g.Organization, g.Repository)
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	newLabel := &github.Label{
+		Name: github.String(g.Label.Name),
+	}
+	if len(g.Label.Color) > 0 {
+		newLabel.Color = github.String(g.Label.Color)
+	}
+	if len(g.Label.Description) > 0 {
+		newLabel.Description = github.String(g.Label.Description)
+	}
+	label, _, err := client.Issues.CreateLabel(ctx, g.Organization, g.Repository, newLabel)
+	if err != nil {
+		return "Failed to Create", fmt.Errorf("unable to create label `%s`. Error: %s", g.Label.Name, err)
+	}
+	return fmt.Sprintf("label `%s` created in `%s/%s`", label.GetName(), g.Organization, g.Repository), nil
+}
+
+func formatLabel(label *github.Label) string {
+	line := fmt.Sprintf("*`%s`*\t`#%s`", label.GetName(), label.GetColor())
+	if len(label.GetDescription()) > 0 {
+		line = line + fmt.Sprintf("\t%s", label.GetDescription())
+	}
+	return line + "\n"
+}
+
+// parseLabelNames accepts `labels=a;b`, `labels=a,b` or `labels=a;labels=b`
+// and returns the individual label names.
+func parseLabelNames(options string) ([]string, error) {
+	var names []string
+	options = strings.TrimSpace(options)
+	if !strings.HasPrefix(options, "labels=") {
+		return nil, fmt.Errorf("labels must be given as `labels=<label>;<label>`")
+	}
+	for _, part := range strings.FieldsFunc(options, func(r rune) bool { return r == ';' || r == ',' }) {
+		name := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(part), "labels="))
+		if len(name) > 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("labels may not be empty")
+	}
+	return names, nil
+}
@@ -31,17 +31,22 @@ func run() error {
 	if len(appToken) == 0 {
 		return fmt.Errorf("the environment variable SLACK_APP_TOKEN must be set")
 	}
-	authToken := os.Getenv("GITHUB_OAUTH_TOKEN")
-	if len(authToken) == 0 {
-		return fmt.Errorf("the environment GITHUB_OAUTH_TOKEN must be set")
-	}
 	githubOrg := os.Getenv("GITHUB_ORG")
 	if len(githubOrg) == 0 {
 		return fmt.Errorf("the environment variable GITHUB_ORG must be set")
 	}
-	githubRepo := os.Getenv("GITHUB_REPO")
-	if len(githubRepo) == 0 {
-		return fmt.Errorf("the environment variable GITHUB_REPO must be set")
+	// GITHUB_REPO is the default repository, commands may name another one
+	if _, err := getConfig(); err != nil {
+		return err
+	}
+	// organizations listed in BOT_CONFIG_FILE bring their own token
+	orgConf, _ := getOrgConfig(githubOrg)
+	authToken := os.Getenv("GITHUB_OAUTH_TOKEN")
+	if len(authToken) == 0 && orgConf == nil {
+		return fmt.Errorf("the environment GITHUB_OAUTH_TOKEN must be set")
+	}
+	if _, _, _, err := staleDigestConfig(); err != nil {
+		return err
 	}
 
 	bot := NewBot(botToken)
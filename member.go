@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"github.com/rs/zerolog/log"
+	"strings"
+	"time"
+)
+
+// teamMembership is the membership of a user in one team of the organization.
+type teamMembership struct {
+	Team       *github.Team
+	Membership *github.Membership
+}
+
+// userTeams returns the teams of the organization the user belongs to or
+// has a pending invitation for. GitHub has no API listing the teams of
+// another user, so every team is checked.
+func (g GithubActions) userTeams() ([]teamMembership, error) {
+	var memberships []teamMembership
+	lstopt := &github.ListOptions{
+		Page:    1,
+		PerPage: 100,
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	for {
+		teams, resp, err := client.Teams.ListTeams(ctx, g.Organization, lstopt)
+		if err != nil {
+			return nil, fmt.Errorf("getting team failed, Error: %s", err)
+		}
+		for _, team := range teams {
+			membership, rsp, err := client.Teams.GetTeamMembershipBySlug(ctx, g.Organization, team.GetSlug(), g.Member.UserName)
+			if err != nil {
+				if rsp != nil && rsp.StatusCode == 404 {
+					continue
+				}
+				return nil, fmt.Errorf("unable to get the membership of `%s` in team `%s`. Error: %s", g.Member.UserName, team.GetSlug(), err)
+			}
+			memberships = append(memberships, teamMembership{Team: team, Membership: membership})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		lstopt.Page = resp.NextPage
+	}
+	return memberships, nil
+}
+
+// memberRemovalPreview describes what `member remove` would take away from
+// the user, for the confirmation message.
+func (g GithubActions) memberRemovalPreview() (string, []string, error) {
+	var teamList []string
+	stat, err := g.validateInputs()
+	if !stat {
+		return "", nil, fmt.Errorf("unknown inputs. Error:%s", err)
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	if !g.Member.FromOrg {
+		membership, rsp, err := client.Teams.GetTeamMembershipBySlug(ctx, g.Organization, g.Member.Team, g.Member.UserName)
+		if err != nil {
+			if rsp != nil && rsp.StatusCode == 404 {
+				return "", nil, fmt.Errorf("user `%s` is not a member of team `%s`", g.Member.UserName, g.Member.Team)
+			}
+			return "", nil, fmt.Errorf("unable to get the membership of `%s` in team `%s`. Error: %s", g.Member.UserName, g.Member.Team, err)
+		}
+		return fmt.Sprintf("Remove `%s` (%s, %s) from team `%s` of `%s`?", g.Member.UserName, membership.GetRole(), membership.GetState(), g.Member.Team, g.Organization), teamList, nil
+	}
+	memberships, err := g.orgRemovalGuard()
+	if err != nil {
+		return "", nil, err
+	}
+	for _, m := range memberships {
+		teamList = append(teamList, fmt.Sprintf("• `%s` (%s, %s)\n", m.Team.GetSlug(), m.Membership.GetRole(), m.Membership.GetState()))
+	}
+	if len(teamList) == 0 {
+		return fmt.Sprintf("Remove `%s` from the organization `%s`? They are in no team.", g.Member.UserName, g.Organization), teamList, nil
+	}
+	return fmt.Sprintf("Remove `%s` from the organization `%s`? They will lose these %d team/s:", g.Member.UserName, g.Organization, len(teamList)), teamList, nil
+}
+
+// orgRemovalGuard refuses to remove an owner of the organization or a member
+// of a protected team from the organization, as removing them from the team
+// is refused. It returns the teams of the user.
+func (g GithubActions) orgRemovalGuard() ([]teamMembership, error) {
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	membership, rsp, err := client.Organizations.GetOrgMembership(ctx, g.Member.UserName, g.Organization)
+	if err != nil {
+		if rsp != nil && rsp.StatusCode == 404 {
+			return nil, fmt.Errorf("user `%s` is not a member of the organization `%s`", g.Member.UserName, g.Organization)
+		}
+		return nil, fmt.Errorf("unable to get the membership of `%s` in `%s`. Error: %s", g.Member.UserName, g.Organization, err)
+	}
+	if membership.GetRole() == "admin" {
+		return nil, fmt.Errorf("You are not privileged to remove `%s`, an owner of the organization `%s`", g.Member.UserName, g.Organization)
+	}
+	memberships, err := g.userTeams()
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range memberships {
+		if isProtectedTeam(m.Team.GetSlug()) {
+			return nil, fmt.Errorf("You are not privileged to remove `%s` from the organization, they are in the `%s` team", g.Member.UserName, m.Team.GetSlug())
+		}
+	}
+	return memberships, nil
+}
+
+// removeMember removes the user from the team, or from the organization and
+// so from all of its teams.
+func (g GithubActions) removeMember(confirmedBy string) (string, error) {
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	if g.Member.FromOrg {
+		// checked again, the membership may have changed since the preview
+		if _, err := g.orgRemovalGuard(); err != nil {
+			return "Not Privileged", err
+		}
+		_, err = client.Organizations.RemoveOrgMembership(ctx, g.Member.UserName, g.Organization)
+		if err != nil {
+			return "Failed to Remove", fmt.Errorf("unable to remove user `%s` from the organization `%s`. Error: %s", g.Member.UserName, g.Organization, err)
+		}
+		log.Info().Msg(fmt.Sprintf("User %s removed from the org %s, confirmed by %s", g.Member.UserName, g.Organization, confirmedBy))
+		return fmt.Sprintf("user `%s` removed from the organization `%s`", g.Member.UserName, g.Organization), nil
+	}
+	_, err = client.Teams.RemoveTeamMembershipBySlug(ctx, g.Organization, g.Member.Team, g.Member.UserName)
+	if err != nil {
+		return "Failed to Remove", fmt.Errorf("unable to remove user `%s` from the team `%s`. Error: %s", g.Member.UserName, g.Member.Team, err)
+	}
+	log.Info().Msg(fmt.Sprintf("User %s removed from the team %s, confirmed by %s", g.Member.UserName, g.Member.Team, confirmedBy))
+	return fmt.Sprintf("user `%s` removed from team `%s`", g.Member.UserName, g.Member.Team), nil
+}
+
+// memberDetails describes the user's membership of the organization, their
+// teams and pending invitations, for `member get`.
+func (g GithubActions) memberDetails() (string, error) {
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	details := fmt.Sprintf("\n*Organization `%s`*\n", g.Organization)
+	membership, rsp, err := client.Organizations.GetOrgMembership(ctx, g.Member.UserName, g.Organization)
+	switch {
+	case err == nil:
+		details = details + fmt.Sprintf("Role:\t*`%s`*\nState:\t*`%s`*\n", membership.GetRole(), membership.GetState())
+	case rsp != nil && rsp.StatusCode == 404:
+		details = details + "Role:\t*`not a member`*\n"
+	default:
+		return "", fmt.Errorf("unable to get the membership of `%s` in `%s`. Error: %s", g.Member.UserName, g.Organization, err)
+	}
+	memberships, err := g.userTeams()
+	if err != nil {
+		return "", err
+	}
+	var teams []string
+	for _, m := range memberships {
+		teams = append(teams, fmt.Sprintf("\t• `%s` %s (%s)\n", m.Team.GetSlug(), m.Membership.GetRole(), m.Membership.GetState()))
+	}
+	if len(teams) == 0 {
+		details = details + "Teams:\t*`none`*\n"
+	} else {
+		details = details + fmt.Sprintf("Teams:\n%s", strings.Join(teams, ""))
+	}
+	// listing invitations needs the org owner scope, the other details are
+	// still useful without them
+	invitations, err := g.userInvitations()
+	if err != nil {
+		log.Info().Msg(fmt.Sprintf("Unable to get the pending invitations of %s in %s, Error: %s", g.Member.UserName, g.Organization, err))
+		return details, nil
+	}
+	var invites []string
+	for _, invitation := range invitations {
+		invites = append(invites, fmt.Sprintf("\t• `%s` for %d team/s, invited by `%s` %s\n", invitation.GetRole(), invitation.GetTeamCount(), invitation.GetInviter().GetLogin(), age(invitation.GetCreatedAt())))
+	}
+	if len(invites) == 0 {
+		details = details + "Pending invitations:\t*`none`*\n"
+	} else {
+		details = details + fmt.Sprintf("Pending invitations:\n%s", strings.Join(invites, ""))
+	}
+	return details, nil
+}
+
+// userInvitations returns the pending invitations of the organization for
+// the user.
+func (g GithubActions) userInvitations() ([]*github.Invitation, error) {
+	var invitations []*github.Invitation
+	lstopt := &github.ListOptions{
+		Page:    1,
+		PerPage: 100,
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	for {
+		pending, resp, err := client.Organizations.ListPendingOrgInvitations(ctx, g.Organization, lstopt)
+		if err != nil {
+			return nil, fmt.Errorf("getting pending invitations failed, Error: %s", err)
+		}
+		for _, invitation := range pending {
+			if strings.EqualFold(invitation.GetLogin(), g.Member.UserName) {
+				invitations = append(invitations, invitation)
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		lstopt.Page = resp.NextPage
+	}
+	return invitations, nil
+}
+
+// age describes how long ago t was in days.
+func age(t time.Time) string {
+	days := int(time.Since(t).Hours() / 24)
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "1 day ago"
+	default:
+		return fmt.Sprintf("%d days ago", days)
+	}
+}
+
+// orNone is value, or `none` for a profile field the user did not set.
+func orNone(value string) string {
+	if len(value) == 0 {
+		return "none"
+	}
+	return value
+}
+
+// changeMemberRole promotes a team member to maintainer or demotes them.
+func (g GithubActions) changeMemberRole() (string, error) {
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	current, rsp, err := client.Teams.GetTeamMembershipBySlug(ctx, g.Organization, g.Member.Team, g.Member.UserName)
+	if err != nil {
+		if rsp != nil && rsp.StatusCode == 404 {
+			return "Not A Member", fmt.Errorf("user `%s` is not a member of team `%s`, msg me `member add` first", g.Member.UserName, g.Member.Team)
+		}
+		return "Internal Error", fmt.Errorf("unable to get the membership of `%s` in team `%s`. Error: %s", g.Member.UserName, g.Member.Team, err)
+	}
+	if current.GetRole() == g.Member.Role {
+		return fmt.Sprintf("user `%s` is already a `%s` of team `%s`", g.Member.UserName, current.GetRole(), g.Member.Team), nil
+	}
+	// adding an existing member updates their role
+	membership, _, err := client.Teams.AddTeamMembershipBySlug(ctx, g.Organization, g.Member.Team, g.Member.UserName, &github.TeamAddTeamMembershipOptions{Role: g.Member.Role})
+	if err != nil {
+		return "Failed to Update", fmt.Errorf("unable to change the role of `%s` in team `%s`. Error: %s", g.Member.UserName, g.Member.Team, err)
+	}
+	log.Info().Msg(fmt.Sprintf("User %s changed from %s to %s in the team %s", g.Member.UserName, current.GetRole(), membership.GetRole(), g.Member.Team))
+	return fmt.Sprintf("user `%s` is now a `%s` of team `%s` (was `%s`)", g.Member.UserName, membership.GetRole(), g.Member.Team, current.GetRole()), nil
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/slack-go/slack"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultOffboardingSchedule is how often deactivated Slack users are looked
+// for when OFFBOARDING_SCHEDULE is not set
+const defaultOffboardingSchedule = "*/15 * * * *"
+
+// offboardingTTL is how long the `Remove from org` button stays valid
+const offboardingTTL = 7 * 24 * time.Hour
+
+// defaultOffboardingFile is where the settled offboardings are kept when
+// OFFBOARDING_STATE_FILE is not set
+const defaultOffboardingFile = "offboarding.json"
+
+var (
+	offboardedMu sync.Mutex
+	// offboarded are the Slack user and organization pairs that are settled:
+	// removed or dismissed from the admins channel, or posted without a
+	// button, with when they were settled
+	offboarded       map[string]time.Time
+	offboardedLoaded bool
+	// offboardingPending are the pairs posted with a `Remove from org` button
+	// by this process, with when the button expires. The buttons do not
+	// survive a restart, so neither does this.
+	offboardingPending = make(map[string]time.Time)
+)
+
+func offboardingFile() string {
+	if len(os.Getenv("OFFBOARDING_STATE_FILE")) > 0 {
+		return os.Getenv("OFFBOARDING_STATE_FILE")
+	}
+	return defaultOffboardingFile
+}
+
+// loadOffboarded reads the offboarding state file the first time it is
+// needed, a missing file is an empty state. offboardedMu must be held.
+func loadOffboarded() error {
+	if offboardedLoaded {
+		return nil
+	}
+	offboarded = make(map[string]time.Time)
+	content, err := ioutil.ReadFile(offboardingFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			offboardedLoaded = true
+			return nil
+		}
+		return fmt.Errorf("unable to read the offboarding state file `%s`. Error: %s", offboardingFile(), err)
+	}
+	if err := json.Unmarshal(content, &offboarded); err != nil {
+		return fmt.Errorf("unable to parse the offboarding state file `%s`. Error: %s", offboardingFile(), err)
+	}
+	offboardedLoaded = true
+	return nil
+}
+
+// setOffboarded records or, when settled is false, forgets the offboarding
+// of the Slack user from the organization.
+func setOffboarded(key string, settled bool) error {
+	offboardedMu.Lock()
+	defer offboardedMu.Unlock()
+	delete(offboardingPending, key)
+	if err := loadOffboarded(); err != nil {
+		return err
+	}
+	if _, ok := offboarded[key]; ok == settled {
+		return nil
+	}
+	if settled {
+		offboarded[key] = time.Now()
+	} else {
+		delete(offboarded, key)
+	}
+	if err := writeJSONFile(offboardingFile(), offboarded); err != nil {
+		return fmt.Errorf("unable to save the offboarding state file `%s`. Error: %s", offboardingFile(), err)
+	}
+	return nil
+}
+
+// isOffboarded reports whether the offboarding is settled or waits for a
+// click on a button that is still valid.
+func isOffboarded(key string) (bool, error) {
+	offboardedMu.Lock()
+	defer offboardedMu.Unlock()
+	if expires, ok := offboardingPending[key]; ok && time.Now().Before(expires) {
+		return true, nil
+	}
+	if err := loadOffboarded(); err != nil {
+		return false, err
+	}
+	_, ok := offboarded[key]
+	return ok, nil
+}
+
+// setOffboardingPending records that the offboarding waits for a click on
+// its button until it expires.
+func setOffboardingPending(key string, expires time.Time) {
+	offboardedMu.Lock()
+	offboardingPending[key] = expires
+	offboardedMu.Unlock()
+}
+
+// forgetOffboardingPending makes the next check post the offboarding again,
+// after its button was used without settling it.
+func forgetOffboardingPending(key string) {
+	offboardedMu.Lock()
+	delete(offboardingPending, key)
+	offboardedMu.Unlock()
+}
+
+// offboardingConfig reads the offboarding settings from the environment. It
+// is disabled when OFFBOARDING_CHANNEL is not set.
+func offboardingConfig() (*cronSchedule, string, error) {
+	channel := os.Getenv("OFFBOARDING_CHANNEL")
+	if len(channel) == 0 {
+		return nil, "", nil
+	}
+	spec := os.Getenv("OFFBOARDING_SCHEDULE")
+	if len(spec) == 0 {
+		spec = defaultOffboardingSchedule
+	}
+	schedule, err := parseCronSchedule(spec)
+	if err != nil {
+		return nil, "", fmt.Errorf("the environment variable OFFBOARDING_SCHEDULE is invalid: %s", err)
+	}
+	return schedule, channel, nil
+}
+
+// startOffboardingWatch posts the GitHub access of deactivated Slack users to
+// the admins channel on the configured schedule until ctx is done. Slacker
+// only dispatches message events, so users.list is polled instead of
+// listening for user_change events.
+func startOffboardingWatch(ctx context.Context, client *slack.Client) {
+	schedule, channel, err := offboardingConfig()
+	if err != nil {
+		log.Info().Msg(err.Error())
+		return
+	}
+	if schedule == nil {
+		return
+	}
+	go runOnSchedule(ctx, "offboarding watch", schedule, func() {
+		if err := checkDeactivatedUsers(client, channel); err != nil {
+			log.Info().Msg(fmt.Sprintf("Unable to check for deactivated Slack users, Error: %s", err))
+		}
+	})
+}
+
+// offboardingOrgs are the organizations the bot has credentials for.
+func offboardingOrgs() []string {
+	var orgs []string
+	if len(os.Getenv("GITHUB_ORG")) > 0 {
+		orgs = append(orgs, os.Getenv("GITHUB_ORG"))
+	}
+	config, err := getConfig()
+	if err != nil {
+		return orgs
+	}
+	for _, org := range config.Organizations {
+		known := false
+		for _, seen := range orgs {
+			known = known || strings.EqualFold(seen, org.Name)
+		}
+		if !known {
+			orgs = append(orgs, org.Name)
+		}
+	}
+	return orgs
+}
+
+// checkDeactivatedUsers posts every deactivated Slack user with a linked
+// GitHub login that still has access to an organization. Settled
+// offboardings are kept in OFFBOARDING_STATE_FILE so a restart does not post
+// them again, and forgotten when the account is reactivated. An offboarding
+// whose button was lost to a restart or expired is posted again.
+func checkDeactivatedUsers(client *slack.Client, channel string) error {
+	linked, err := linkedIdentities()
+	if err != nil {
+		return err
+	}
+	if len(linked) == 0 {
+		return nil
+	}
+	users, err := client.GetUsers()
+	if err != nil {
+		return fmt.Errorf("unable to list the Slack users, Error: %s", err)
+	}
+	for _, user := range users {
+		id, ok := linked[user.ID]
+		if !ok {
+			continue
+		}
+		for _, org := range offboardingOrgs() {
+			key := user.ID + "/" + strings.ToLower(org)
+			if !user.Deleted {
+				if err := setOffboarded(key, false); err != nil {
+					return err
+				}
+				continue
+			}
+			done, err := isOffboarded(key)
+			if err != nil {
+				return err
+			}
+			if done {
+				continue
+			}
+			githubAct := GithubActions{
+				Organization: org,
+				Member: &MemberAction{
+					UserName: id.Login,
+					Action:   "remove",
+					FromOrg:  true,
+				},
+			}
+			if err := githubAct.postOffboarding(client, channel, user, key); err != nil {
+				log.Info().Msg(fmt.Sprintf("Unable to post the offboarding of %s from %s, Error: %s", id.Login, org, err))
+			}
+		}
+	}
+	return nil
+}
+
+// postOffboarding posts the memberships of the deactivated user in the
+// organization to the admins channel, with a button removing them from the
+// organization when they are a member. Nothing is posted without access.
+// key is settled once the post needs no click, or once it is clicked.
+func (g GithubActions) postOffboarding(client *slack.Client, channel string, user slack.User, key string) error {
+	githubClient, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	_, rsp, err := githubClient.Organizations.GetOrgMembership(ctx, g.Member.UserName, g.Organization)
+	isMember := err == nil
+	if err != nil && (rsp == nil || rsp.StatusCode != 404) {
+		return fmt.Errorf("unable to get the membership of `%s` in `%s`. Error: %s", g.Member.UserName, g.Organization, err)
+	}
+	invitations, err := g.userInvitations()
+	if err != nil {
+		return err
+	}
+	if !isMember && len(invitations) == 0 {
+		return setOffboarded(key, true)
+	}
+	details, err := g.memberDetails()
+	if err != nil {
+		return err
+	}
+	text := fmt.Sprintf("The Slack account of <@%s> (%s) was deactivated, its linked GitHub login `%s` still has access to `%s`:", user.ID, user.RealName, g.Member.UserName, g.Organization)
+	if !isMember {
+		err := postWithList(client, channel, text, []string{details, fmt.Sprintf("\nmsg me `invite cancel %s org=%s` to cancel the pending invitations", g.Member.UserName, g.Organization)})
+		if err != nil {
+			return err
+		}
+		return setOffboarded(key, true)
+	}
+	admins, err := channelMembers(client, channel)
+	if err != nil {
+		return err
+	}
+	setOffboardingPending(key, time.Now().Add(offboardingTTL))
+	err = askConfirmation(client, channel, text, []string{details}, &confirmation{
+		Approvers:    admins,
+		Summary:      fmt.Sprintf("offboarding of %s from %s", g.Member.UserName, g.Organization),
+		ConfirmLabel: "Remove from org",
+		CancelLabel:  "Dismiss",
+		TTL:          offboardingTTL,
+		Run: func(confirmedBy string) string {
+			msg, err := g.removeMember(confirmedBy)
+			if err != nil {
+				// post it again with a new button on the next check
+				forgetOffboardingPending(key)
+				return err.Error()
+			}
+			if err := setOffboarded(key, true); err != nil {
+				log.Info().Msg(err.Error())
+			}
+			return msg
+		},
+		OnCancel: func(cancelledBy string) string {
+			if err := setOffboarded(key, true); err != nil {
+				log.Info().Msg(err.Error())
+			}
+			return "it will not be posted again unless the account is reactivated"
+		},
+	})
+	if err != nil {
+		forgetOffboardingPending(key)
+	}
+	return err
+}
+
+// channelMembers returns the Slack users in the channel.
+func channelMembers(client *slack.Client, channel string) ([]string, error) {
+	var members []string
+	params := &slack.GetUsersInConversationParameters{ChannelID: channel, Limit: 200}
+	for {
+		page, cursor, err := client.GetUsersInConversation(params)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list the members of the channel %s, Error: %s", channel, err)
+		}
+		members = append(members, page...)
+		if len(cursor) == 0 {
+			break
+		}
+		params.Cursor = cursor
+	}
+	return members, nil
+}
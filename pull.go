@@ -0,0 +1,624 @@
+package main
+
+import (
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"os"
+	"sort"
+	"strings"
+)
+
+func (g GithubActions) actOnPull() (bool, []string, string, error) {
+	var pullList []string
+	stat, message, err := g.validateRepoAndOrg()
+	if !stat {
+		return false, pullList, message, fmt.Errorf("invalid org/repo. Error: %s", err)
+	}
+	stat, err = g.validateInputs()
+	if !stat {
+		return false, pullList, "Unknown Options", fmt.Errorf("unknown inputs, Error:%s", err)
+	}
+	switch {
+	case g.Pull.Action == "list":
+		pullList, message, err = g.listPulls()
+	case g.Pull.Action == "show":
+		pullList, message, err = g.showPull()
+	case g.Pull.Action == "reviews":
+		pullList, message, err = g.pullReviews()
+	case g.Pull.Action == "merge":
+		pullList, message, err = g.mergePull()
+	default:
+		return false, pullList, "", fmt.Errorf("unknown Action")
+	}
+	if err != nil {
+		return false, pullList, message, err
+	}
+	return true, pullList, message, nil
+}
+
+func (g GithubActions) listPulls() ([]string, string, error) {
+	var pullList []string
+	state := g.Pull.State
+	if len(state) == 0 {
+		state = "open"
+	}
+	opts := &github.PullRequestListOptions{
+		State: state,
+		ListOptions: github.ListOptions{
+			Page:    1,
+			PerPage: 100,
+		},
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	for {
+		pulls, resp, err := client.PullRequests.List(ctx, g.Organization, g.Repository, opts)
+		if err != nil {
+			return nil, "Internal Error", fmt.Errorf("getting pull requests failed, Error: %s", err)
+		}
+		for _, pull := range pulls {
+			if g.Pull.matches(pull) {
+				pullList = append(pullList, formatPull(pull))
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.ListOptions.Page = resp.NextPage
+	}
+	if len(pullList) == 0 {
+		return pullList, fmt.Sprintf("No `%s` pull requests found in `%s/%s`", state, g.Organization, g.Repository), nil
+	}
+	return pullList, fmt.Sprintf("%d pull request/s found", len(pullList)), nil
+}
+
+// matches applies the author, label and reviewer filters, which the pull
+// request list API does not support.
+func (p *PullAction) matches(pull *github.PullRequest) bool {
+	if len(p.Author) > 0 && !strings.EqualFold(pull.GetUser().GetLogin(), p.Author) {
+		return false
+	}
+	for _, name := range p.Labels {
+		found := false
+		for _, label := range pull.Labels {
+			if strings.EqualFold(label.GetName(), name) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(p.Reviewer) > 0 {
+		for _, reviewer := range pull.RequestedReviewers {
+			if strings.EqualFold(reviewer.GetLogin(), p.Reviewer) {
+				return true
+			}
+		}
+		for _, team := range pull.RequestedTeams {
+			if strings.EqualFold(team.GetSlug(), p.Reviewer) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func formatPull(pull *github.PullRequest) string {
+	line := fmt.Sprintf("*<%s|#%d>*\t%s\tby `%s`", pull.GetHTMLURL(), pull.GetNumber(), pull.GetTitle(), pull.GetUser().GetLogin())
+	if pull.GetDraft() {
+		line = line + "\t`draft`"
+	}
+	if len(pull.Labels) > 0 {
+		line = line + fmt.Sprintf("\tlabels: `%s`", labelNames(pull.Labels))
+	}
+	if len(pull.RequestedReviewers) > 0 {
+		line = line + fmt.Sprintf("\treviewers: `%s`", userLogins(pull.RequestedReviewers))
+	}
+	return line + "\n"
+}
+
+func (g GithubActions) showPull() ([]string, string, error) {
+	var details []string
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	pull, _, err := client.PullRequests.Get(ctx, g.Organization, g.Repository, g.Pull.Number)
+	if err != nil {
+		return nil, "Unknown Pull Request", fmt.Errorf("unable to get pull request `#%d`. Error: %s", g.Pull.Number, err)
+	}
+	state := pull.GetState()
+	if pull.GetMerged() {
+		state = "merged"
+	} else if pull.GetDraft() {
+		state = "draft"
+	}
+	details = append(details, fmt.Sprintf("State:\t`%s`\n", state))
+	details = append(details, fmt.Sprintf("Author:\t`%s`\n", pull.GetUser().GetLogin()))
+	details = append(details, fmt.Sprintf("Branch:\t`%s` into `%s`\n", pull.GetHead().GetRef(), pull.GetBase().GetRef()))
+	details = append(details, fmt.Sprintf("Changes:\t`%d` file/s, `+%d` `-%d` in `%d` commit/s\n", pull.GetChangedFiles(), pull.GetAdditions(), pull.GetDeletions(), pull.GetCommits()))
+	details = append(details, fmt.Sprintf("Mergeable:\t`%s`\n", mergeableState(pull)))
+	details = append(details, fmt.Sprintf("Labels:\t`%s`\n", labelNames(pull.Labels)))
+
+	reviews, err := latestReviews(g.Organization, g.Repository, g.Pull.Number)
+	if err != nil {
+		return nil, "Internal Error", err
+	}
+	details = append(details, fmt.Sprintf("Reviews:\t%s\n", summarizeReviews(reviews)))
+	if len(pull.RequestedReviewers) > 0 {
+		details = append(details, fmt.Sprintf("Waiting for:\t`%s`\n", userLogins(pull.RequestedReviewers)))
+	}
+
+	checks, err := refChecks(g.Organization, g.Repository, pull.GetHead().GetSHA())
+	if err != nil {
+		return nil, "Internal Error", err
+	}
+	details = append(details, fmt.Sprintf("CI:\t%s\n", summarizeChecks(checks)))
+	protection, err := branchProtection(g.Organization, g.Repository, pull.GetBase().GetRef())
+	if err != nil {
+		return nil, "Internal Error", err
+	}
+	required := requiredChecks(protection)
+	if len(required) == 0 {
+		details = append(details, "Required checks:\t`none`\n")
+	} else {
+		details = append(details, "Required checks:\n")
+		for _, name := range required {
+			state, ok := checks[name]
+			if !ok {
+				state = "missing"
+			}
+			details = append(details, fmt.Sprintf("\t%s `%s`: %s\n", checkEmoji(state), name, state))
+		}
+	}
+	return details, fmt.Sprintf("*<%s|#%d>* %s", pull.GetHTMLURL(), pull.GetNumber(), pull.GetTitle()), nil
+}
+
+func (g GithubActions) pullReviews() ([]string, string, error) {
+	var reviewList []string
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	pull, _, err := client.PullRequests.Get(ctx, g.Organization, g.Repository, g.Pull.Number)
+	if err != nil {
+		return nil, "Unknown Pull Request", fmt.Errorf("unable to get pull request `#%d`. Error: %s", g.Pull.Number, err)
+	}
+	reviews, err := latestReviews(g.Organization, g.Repository, g.Pull.Number)
+	if err != nil {
+		return nil, "Internal Error", err
+	}
+	var reviewers []string
+	for reviewer := range reviews {
+		reviewers = append(reviewers, reviewer)
+	}
+	sort.Strings(reviewers)
+	for _, reviewer := range reviewers {
+		reviewList = append(reviewList, fmt.Sprintf("%s `%s`: %s\n", reviewEmoji(reviews[reviewer]), reviewer, strings.ToLower(strings.ReplaceAll(reviews[reviewer], "_", " "))))
+	}
+	for _, reviewer := range pull.RequestedReviewers {
+		reviewList = append(reviewList, fmt.Sprintf(":hourglass: `%s`: review requested\n", reviewer.GetLogin()))
+	}
+	for _, team := range pull.RequestedTeams {
+		reviewList = append(reviewList, fmt.Sprintf(":hourglass: team `%s`: review requested\n", team.GetSlug()))
+	}
+	if len(reviewList) == 0 {
+		return reviewList, fmt.Sprintf("No reviews on pull request `#%d`", g.Pull.Number), nil
+	}
+	return reviewList, fmt.Sprintf("Reviews on *<%s|#%d>*", pull.GetHTMLURL(), pull.GetNumber()), nil
+}
+
+// mergeGate is one of the conditions a pull request has to meet before the
+// bot merges it.
+type mergeGate struct {
+	Name   string
+	Passed bool
+	Reason string
+}
+
+// mergePull merges the pull request when every merge gate passes, otherwise
+// it reports the state of every gate.
+func (g GithubActions) mergePull() ([]string, string, error) {
+	var report []string
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	pull, _, err := client.PullRequests.Get(ctx, g.Organization, g.Repository, g.Pull.Number)
+	if err != nil {
+		return nil, "Unknown Pull Request", fmt.Errorf("unable to get pull request `#%d`. Error: %s", g.Pull.Number, err)
+	}
+	gates, err := g.mergeGates(pull)
+	if err != nil {
+		return nil, "Internal Error", err
+	}
+	passed := true
+	for _, gate := range gates {
+		emoji := ":white_check_mark:"
+		if !gate.Passed {
+			emoji = ":x:"
+			passed = false
+		}
+		report = append(report, fmt.Sprintf("%s %s: %s\n", emoji, gate.Name, gate.Reason))
+	}
+	if !passed {
+		return report, fmt.Sprintf("*<%s|#%d>* can not be merged", pull.GetHTMLURL(), pull.GetNumber()), nil
+	}
+	method := g.Pull.Method
+	if len(method) == 0 {
+		method = "merge"
+	}
+	// SHA makes GitHub refuse the merge if new commits were pushed after the checks
+	result, _, err := client.PullRequests.Merge(ctx, g.Organization, g.Repository, g.Pull.Number, "", &github.PullRequestOptions{
+		MergeMethod: method,
+		SHA:         pull.GetHead().GetSHA(),
+	})
+	if err != nil {
+		return report, "Failed to Merge", fmt.Errorf("unable to merge pull request `#%d`. Error: %s", g.Pull.Number, err)
+	}
+	if !result.GetMerged() {
+		return report, "Failed to Merge", fmt.Errorf("unable to merge pull request `#%d`. %s", g.Pull.Number, result.GetMessage())
+	}
+	return report, fmt.Sprintf("*<%s|#%d>* merged with `%s` as `%.7s`", pull.GetHTMLURL(), pull.GetNumber(), method, result.GetSHA()), nil
+}
+
+func (g GithubActions) mergeGates(pull *github.PullRequest) ([]mergeGate, error) {
+	var gates []mergeGate
+	authorised, err := g.mergeRequesterGate()
+	if err != nil {
+		return nil, err
+	}
+	gates = append(gates, authorised)
+
+	state := mergeGate{Name: "State", Passed: pull.GetState() == "open" && !pull.GetDraft() && pull.GetMergeable()}
+	switch {
+	case pull.GetMerged():
+		state.Reason = "already merged"
+	case pull.GetState() != "open":
+		state.Reason = "pull request is closed"
+	case pull.GetDraft():
+		state.Reason = "pull request is a draft"
+	default:
+		state.Reason = "mergeable: " + mergeableState(pull)
+	}
+	gates = append(gates, state)
+
+	protection, err := branchProtection(g.Organization, g.Repository, pull.GetBase().GetRef())
+	if err != nil {
+		return nil, err
+	}
+	checks, err := refChecks(g.Organization, g.Repository, pull.GetHead().GetSHA())
+	if err != nil {
+		return nil, err
+	}
+	// without required checks on the branch every reported check must pass
+	required := requiredChecks(protection)
+	if len(required) == 0 {
+		for name := range checks {
+			required = append(required, name)
+		}
+		sort.Strings(required)
+	}
+	var failing []string
+	for _, name := range required {
+		if checks[name] != "success" {
+			state, ok := checks[name]
+			if !ok {
+				state = "missing"
+			}
+			failing = append(failing, fmt.Sprintf("`%s` is %s", name, state))
+		}
+	}
+	ci := mergeGate{Name: "Checks", Passed: len(failing) == 0}
+	if ci.Passed {
+		ci.Reason = fmt.Sprintf("%d required check/s passed", len(required))
+	} else {
+		ci.Reason = strings.Join(failing, ", ")
+	}
+	gates = append(gates, ci)
+
+	reviews, err := latestReviews(g.Organization, g.Repository, g.Pull.Number)
+	if err != nil {
+		return nil, err
+	}
+	approvals := 1
+	if protection != nil && protection.RequiredPullRequestReviews != nil && protection.RequiredPullRequestReviews.RequiredApprovingReviewCount > 0 {
+		approvals = protection.RequiredPullRequestReviews.RequiredApprovingReviewCount
+	}
+	approved := 0
+	var changesRequested, ignored []string
+	for reviewer, state := range reviews {
+		if state != "APPROVED" && state != "CHANGES_REQUESTED" {
+			continue
+		}
+		// like branch protection, only reviewers with write access count
+		canWrite, err := hasWriteAccess(g.Organization, g.Repository, reviewer)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case !canWrite:
+			ignored = append(ignored, reviewer)
+		case state == "APPROVED":
+			approved++
+		default:
+			changesRequested = append(changesRequested, reviewer)
+		}
+	}
+	sort.Strings(changesRequested)
+	sort.Strings(ignored)
+	review := mergeGate{Name: "Approvals", Passed: approved >= approvals && len(changesRequested) == 0}
+	switch {
+	case len(changesRequested) > 0:
+		review.Reason = fmt.Sprintf("changes requested by `%s`", strings.Join(changesRequested, ", "))
+	default:
+		review.Reason = fmt.Sprintf("%d of %d required approval/s", approved, approvals)
+	}
+	if len(ignored) > 0 {
+		review.Reason = fmt.Sprintf("%s, reviews of `%s` without write access are not counted", review.Reason, strings.Join(ignored, ", "))
+	}
+	gates = append(gates, review)
+	return gates, nil
+}
+
+// mergeRequesterGate passes when the requester is in MERGE_ALLOWED_SLACK_USERS
+// or their linked GitHub login has write access to the repository.
+func (g GithubActions) mergeRequesterGate() (mergeGate, error) {
+	gate := mergeGate{Name: "Requester"}
+	if isMergeAllowed(g.Pull.Requester) {
+		gate.Passed = true
+		gate.Reason = "allowed to merge"
+		return gate, nil
+	}
+	login, err := linkedLogin(g.Pull.Requester)
+	if err != nil {
+		return gate, err
+	}
+	if len(login) == 0 {
+		gate.Reason = fmt.Sprintf("<@%s> is not allowed to merge in `%s/%s`, msg me `link <github login>` to merge with your own GitHub permissions", g.Pull.Requester, g.Organization, g.Repository)
+		return gate, nil
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return gate, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	level, _, err := client.Repositories.GetPermissionLevel(ctx, g.Organization, g.Repository, login)
+	if err != nil {
+		return gate, fmt.Errorf("unable to get the permission of `%s` on `%s`. Error: %s", login, g.Repository, err)
+	}
+	// GetPermissionLevel reports maintain as write
+	gate.Passed = level.GetPermission() == "admin" || level.GetPermission() == "write"
+	if gate.Passed {
+		gate.Reason = fmt.Sprintf("<@%s> is `%s` with `%s` access", g.Pull.Requester, login, level.GetPermission())
+	} else {
+		gate.Reason = fmt.Sprintf("<@%s> is `%s` with `%s` access, write access is needed to merge in `%s/%s`", g.Pull.Requester, login, level.GetPermission(), g.Organization, g.Repository)
+	}
+	return gate, nil
+}
+
+// isMergeAllowed reports whether the Slack user is listed in
+// MERGE_ALLOWED_SLACK_USERS.
+func isMergeAllowed(slackUser string) bool {
+	if len(slackUser) == 0 {
+		return false
+	}
+	for _, user := range strings.Split(os.Getenv("MERGE_ALLOWED_SLACK_USERS"), ",") {
+		if strings.TrimSpace(user) == slackUser {
+			return true
+		}
+	}
+	return false
+}
+
+func mergeableState(pull *github.PullRequest) string {
+	if pull.GetMerged() {
+		return "already merged"
+	}
+	// GitHub computes mergeability in the background
+	if pull.Mergeable == nil {
+		return "unknown, try again in a moment"
+	}
+	if !pull.GetMergeable() {
+		return "no, " + pull.GetMergeableState()
+	}
+	return "yes, " + pull.GetMergeableState()
+}
+
+// hasWriteAccess reports whether the GitHub user has write, maintain or
+// admin permission on the repository.
+func hasWriteAccess(owner string, repo string, login string) (bool, error) {
+	client, ctx, err := getGitClient(owner)
+	if err != nil {
+		return false, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	level, _, err := client.Repositories.GetPermissionLevel(ctx, owner, repo, login)
+	if err != nil {
+		return false, fmt.Errorf("unable to get the permission of `%s` on `%s`. Error: %s", login, repo, err)
+	}
+	// GetPermissionLevel reports maintain as write
+	return level.GetPermission() == "admin" || level.GetPermission() == "write", nil
+}
+
+// latestReviews returns the latest review state of every reviewer, comments
+// do not change the decision of a reviewer.
+func latestReviews(owner string, repo string, number int) (map[string]string, error) {
+	latest := make(map[string]string)
+	lstopt := &github.ListOptions{
+		Page:    1,
+		PerPage: 100,
+	}
+	client, ctx, err := getGitClient(owner)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	for {
+		reviews, resp, err := client.PullRequests.ListReviews(ctx, owner, repo, number, lstopt)
+		if err != nil {
+			return nil, fmt.Errorf("getting reviews failed, Error: %s", err)
+		}
+		for _, review := range reviews {
+			if review.GetState() == "COMMENTED" || review.GetState() == "PENDING" {
+				continue
+			}
+			latest[review.GetUser().GetLogin()] = review.GetState()
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		lstopt.Page = resp.NextPage
+	}
+	return latest, nil
+}
+
+// refChecks returns the state of every commit status and check run of ref
+// as success, failure or pending.
+func refChecks(owner string, repo string, ref string) (map[string]string, error) {
+	checks := make(map[string]string)
+	client, ctx, err := getGitClient(owner)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	status, _, err := client.Repositories.GetCombinedStatus(ctx, owner, repo, ref, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, fmt.Errorf("getting commit status failed, Error: %s", err)
+	}
+	for _, s := range status.Statuses {
+		switch s.GetState() {
+		case "success", "pending":
+			checks[s.GetContext()] = s.GetState()
+		default:
+			checks[s.GetContext()] = "failure"
+		}
+	}
+	runs, _, err := client.Checks.ListCheckRunsForRef(ctx, owner, repo, ref, &github.ListCheckRunsOptions{ListOptions: github.ListOptions{PerPage: 100}})
+	if err != nil {
+		return nil, fmt.Errorf("getting check runs failed, Error: %s", err)
+	}
+	for _, run := range runs.CheckRuns {
+		switch {
+		case run.GetStatus() != "completed":
+			checks[run.GetName()] = "pending"
+		case run.GetConclusion() == "success" || run.GetConclusion() == "neutral" || run.GetConclusion() == "skipped":
+			checks[run.GetName()] = "success"
+		default:
+			checks[run.GetName()] = "failure"
+		}
+	}
+	return checks, nil
+}
+
+// branchProtection returns nil when the branch is not protected.
+func branchProtection(owner string, repo string, branch string) (*github.Protection, error) {
+	client, ctx, err := getGitClient(owner)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	protection, resp, err := client.Repositories.GetBranchProtection(ctx, owner, repo, branch)
+	if err != nil {
+		if resp != nil && resp.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting protection of branch `%s` failed, Error: %s", branch, err)
+	}
+	return protection, nil
+}
+
+func requiredChecks(protection *github.Protection) []string {
+	var required []string
+	if protection == nil || protection.RequiredStatusChecks == nil {
+		return required
+	}
+	required = append(required, protection.RequiredStatusChecks.Contexts...)
+	for _, check := range protection.RequiredStatusChecks.Checks {
+		if !contains(required, check.Context) {
+			required = append(required, check.Context)
+		}
+	}
+	return required
+}
+
+func summarizeReviews(reviews map[string]string) string {
+	counts := make(map[string]int)
+	for _, state := range reviews {
+		counts[state]++
+	}
+	var summary []string
+	if counts["APPROVED"] > 0 {
+		summary = append(summary, fmt.Sprintf(":white_check_mark: %d approved", counts["APPROVED"]))
+	}
+	if counts["CHANGES_REQUESTED"] > 0 {
+		summary = append(summary, fmt.Sprintf(":x: %d changes requested", counts["CHANGES_REQUESTED"]))
+	}
+	if counts["DISMISSED"] > 0 {
+		summary = append(summary, fmt.Sprintf("%d dismissed", counts["DISMISSED"]))
+	}
+	if len(summary) == 0 {
+		return "no reviews"
+	}
+	return strings.Join(summary, ", ")
+}
+
+func summarizeChecks(checks map[string]string) string {
+	counts := make(map[string]int)
+	for _, state := range checks {
+		counts[state]++
+	}
+	var summary []string
+	if counts["failure"] > 0 {
+		summary = append(summary, fmt.Sprintf(":x: %d failed", counts["failure"]))
+	}
+	if counts["pending"] > 0 {
+		summary = append(summary, fmt.Sprintf(":hourglass: %d pending", counts["pending"]))
+	}
+	if counts["success"] > 0 {
+		summary = append(summary, fmt.Sprintf(":white_check_mark: %d passed", counts["success"]))
+	}
+	if len(summary) == 0 {
+		return "no checks"
+	}
+	return strings.Join(summary, ", ")
+}
+
+// reviewSummary is the review status shown on unfurled pull requests.
+func reviewSummary(owner string, repo string, number int) string {
+	reviews, err := latestReviews(owner, repo, number)
+	if err != nil {
+		return "unknown"
+	}
+	return summarizeReviews(reviews)
+}
+
+// ciSummary is the CI status shown on unfurled pull requests and commits.
+func ciSummary(owner string, repo string, ref string) string {
+	checks, err := refChecks(owner, repo, ref)
+	if err != nil {
+		return "unknown"
+	}
+	return summarizeChecks(checks)
+}
+
+func checkEmoji(state string) string {
+	switch state {
+	case "success":
+		return ":white_check_mark:"
+	case "pending":
+		return ":hourglass:"
+	default:
+		return ":x:"
+	}
+}
+
+func reviewEmoji(state string) string {
+	switch state {
+	case "APPROVED":
+		return ":white_check_mark:"
+	case "CHANGES_REQUESTED":
+		return ":x:"
+	default:
+		return ":grey_question:"
+	}
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"github.com/rs/zerolog/log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultInactiveDays is the window of `report inactive` without days=
+const defaultInactiveDays = 90
+
+// maxInactiveDays limits the window of `report inactive`, every day adds
+// activity to page through
+const maxInactiveDays = 365
+
+// noTeam groups the members that are in no reported team.
+const noTeam = "(no team)"
+
+// report is the result of a `report` command: a summary with a short list
+// for Slack and, for long reports, a CSV file.
+type report struct {
+	Summary  string
+	List     []string
+	Filename string
+	CSV      string
+}
+
+func (g GithubActions) actOnReport() (bool, report, error) {
+	stat, message, err := g.validateOrg()
+	if !stat {
+		return false, report{}, fmt.Errorf("%s. Error: %s", message, err)
+	}
+	stat, err = g.validateInputs()
+	if !stat {
+		return false, report{}, fmt.Errorf("unknown inputs, Error:%s", err)
+	}
+	switch g.Report.Type {
+	case "inactive":
+		return g.inactiveReport()
+	case "2fa":
+		return g.twoFactorReport()
+	default:
+		return false, report{}, fmt.Errorf("unknown report")
+	}
+}
+
+// orgMembers returns the logins of the members of the organization, filter
+// is empty or `2fa_disabled`.
+func (g GithubActions) orgMembers(filter string) ([]string, error) {
+	var members []string
+	lstopt := &github.ListMembersOptions{
+		Filter: filter,
+		ListOptions: github.ListOptions{
+			Page:    1,
+			PerPage: 100,
+		},
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	for {
+		users, resp, err := client.Organizations.ListMembers(ctx, g.Organization, lstopt)
+		if err != nil {
+			return nil, fmt.Errorf("getting the members of `%s` failed, Error: %s", g.Organization, err)
+		}
+		for _, user := range users {
+			members = append(members, user.GetLogin())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		lstopt.Page = resp.NextPage
+	}
+	return members, nil
+}
+
+// memberTeams maps the members of the organization to the slugs of their
+// teams, the teams in ExcludeTeamName are left out.
+func (g GithubActions) memberTeams() (map[string][]string, error) {
+	teams := make(map[string][]string)
+	lstopt := &github.ListOptions{
+		Page:    1,
+		PerPage: 100,
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	for {
+		page, resp, err := client.Teams.ListTeams(ctx, g.Organization, lstopt)
+		if err != nil {
+			return nil, fmt.Errorf("getting team failed, Error: %s", err)
+		}
+		for _, team := range page {
+			if isProtectedTeam(team.GetSlug()) {
+				continue
+			}
+			opts := &github.TeamListTeamMembersOptions{
+				Role: "all",
+				ListOptions: github.ListOptions{
+					Page:    1,
+					PerPage: 100,
+				},
+			}
+			for {
+				members, resp, err := client.Teams.ListTeamMembersBySlug(ctx, g.Organization, team.GetSlug(), opts)
+				if err != nil {
+					return nil, fmt.Errorf("getting the members of team `%s` failed, Error: %s", team.GetSlug(), err)
+				}
+				for _, member := range members {
+					login := strings.ToLower(member.GetLogin())
+					teams[login] = append(teams[login], team.GetSlug())
+				}
+				if resp.NextPage == 0 {
+					break
+				}
+				opts.Page = resp.NextPage
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		lstopt.Page = resp.NextPage
+	}
+	return teams, nil
+}
+
+// activeLogins returns the users with a commit on the default branch, an
+// issue or pull request, a review or a comment in the repository since the
+// given time. The logins are lower case.
+func (g GithubActions) activeLogins(repo string, since time.Time, active map[string]bool) error {
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	mark := func(user *github.User) {
+		if len(user.GetLogin()) > 0 {
+			active[strings.ToLower(user.GetLogin())] = true
+		}
+	}
+
+	commitOpts := &github.CommitsListOptions{Since: since, ListOptions: github.ListOptions{Page: 1, PerPage: 100}}
+	for {
+		commits, resp, err := client.Repositories.ListCommits(ctx, g.Organization, repo, commitOpts)
+		if err != nil {
+			// an empty repository has no commits to list
+			if resp != nil && resp.StatusCode == 409 {
+				break
+			}
+			return fmt.Errorf("getting the commits of `%s` failed, Error: %s", repo, err)
+		}
+		for _, commit := range commits {
+			mark(commit.GetAuthor())
+			mark(commit.GetCommitter())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		commitOpts.Page = resp.NextPage
+	}
+
+	var pulls []int
+	issueOpts := &github.IssueListByRepoOptions{State: "all", Since: since, ListOptions: github.ListOptions{Page: 1, PerPage: 100}}
+	for {
+		issues, resp, err := client.Issues.ListByRepo(ctx, g.Organization, repo, issueOpts)
+		if err != nil {
+			return fmt.Errorf("getting the issues of `%s` failed, Error: %s", repo, err)
+		}
+		for _, issue := range issues {
+			if issue.GetCreatedAt().After(since) {
+				mark(issue.GetUser())
+			}
+			if issue.IsPullRequest() {
+				pulls = append(pulls, issue.GetNumber())
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		issueOpts.Page = resp.NextPage
+	}
+
+	commentOpts := &github.IssueListCommentsOptions{Since: &since, ListOptions: github.ListOptions{Page: 1, PerPage: 100}}
+	for {
+		// issue number 0 lists the comments of every issue
+		comments, resp, err := client.Issues.ListComments(ctx, g.Organization, repo, 0, commentOpts)
+		if err != nil {
+			return fmt.Errorf("getting the comments of `%s` failed, Error: %s", repo, err)
+		}
+		for _, comment := range comments {
+			mark(comment.GetUser())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		commentOpts.Page = resp.NextPage
+	}
+
+	reviewCommentOpts := &github.PullRequestListCommentsOptions{Since: since, ListOptions: github.ListOptions{Page: 1, PerPage: 100}}
+	for {
+		comments, resp, err := client.PullRequests.ListComments(ctx, g.Organization, repo, 0, reviewCommentOpts)
+		if err != nil {
+			return fmt.Errorf("getting the review comments of `%s` failed, Error: %s", repo, err)
+		}
+		for _, comment := range comments {
+			mark(comment.GetUser())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		reviewCommentOpts.Page = resp.NextPage
+	}
+
+	// a review without comments only shows on its pull request
+	for _, number := range pulls {
+		reviewOpts := &github.ListOptions{Page: 1, PerPage: 100}
+		for {
+			reviews, resp, err := client.PullRequests.ListReviews(ctx, g.Organization, repo, number, reviewOpts)
+			if err != nil {
+				return fmt.Errorf("getting the reviews of `%s#%d` failed, Error: %s", repo, number, err)
+			}
+			for _, review := range reviews {
+				if review.GetSubmittedAt().After(since) {
+					mark(review.GetUser())
+				}
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			reviewOpts.Page = resp.NextPage
+		}
+	}
+	return nil
+}
+
+// inactiveReport finds the members of the organization without activity in
+// its repositories in the last g.Report.Days days, grouped by team.
+func (g GithubActions) inactiveReport() (bool, report, error) {
+	since := time.Now().AddDate(0, 0, -g.Report.Days)
+	repos, err := g.orgRepos()
+	if err != nil {
+		return false, report{}, err
+	}
+	active := make(map[string]bool)
+	for _, repo := range repos {
+		// an archived repository can not have new activity
+		if repo.GetArchived() {
+			continue
+		}
+		if err := g.activeLogins(repo.GetName(), since, active); err != nil {
+			return false, report{}, err
+		}
+	}
+	members, err := g.orgMembers("")
+	if err != nil {
+		return false, report{}, err
+	}
+	teams, err := g.memberTeams()
+	if err != nil {
+		return false, report{}, err
+	}
+	inactive := make(map[string][]string)
+	count := 0
+	for _, member := range members {
+		if active[strings.ToLower(member)] {
+			continue
+		}
+		count++
+		memberOf := teams[strings.ToLower(member)]
+		if len(memberOf) == 0 {
+			memberOf = []string{noTeam}
+		}
+		for _, team := range memberOf {
+			inactive[team] = append(inactive[team], member)
+		}
+	}
+	log.Info().Msg(fmt.Sprintf("Inactive report of %s: %d of %d members inactive for %d days", g.Organization, count, len(members), g.Report.Days))
+	result := report{
+		Summary: fmt.Sprintf("%d of %d member/s of `%s` had no commits, issues, pull requests, reviews or comments in its repositories in the last %d days", count, len(members), g.Organization, g.Report.Days),
+	}
+	if count == 0 {
+		return true, result, nil
+	}
+	list, content, err := inactiveMembersCSV(inactive, since)
+	if err != nil {
+		return false, report{}, fmt.Errorf("unable to write the report, Error: %s", err)
+	}
+	result.List = list
+	result.CSV = content
+	result.Filename = fmt.Sprintf("inactive-members-%s-%s.csv", g.Organization, time.Now().Format("2006-01-02"))
+	return true, result, nil
+}
+
+// inactiveMembersCSV returns the inactive member count of every team and a
+// CSV of the inactive members by team, both sorted.
+func inactiveMembersCSV(inactive map[string][]string, since time.Time) ([]string, string, error) {
+	var teamNames []string
+	for team := range inactive {
+		teamNames = append(teamNames, team)
+	}
+	sort.Strings(teamNames)
+	var list []string
+	var content bytes.Buffer
+	writer := csv.NewWriter(&content)
+	writer.Write([]string{"team", "github_login", "inactive_since"})
+	for _, team := range teamNames {
+		sort.Strings(inactive[team])
+		list = append(list, fmt.Sprintf("`%s`\t%d inactive member/s\n", team, len(inactive[team])))
+		for _, member := range inactive[team] {
+			writer.Write([]string{team, member, since.Format("2006-01-02")})
+		}
+	}
+	writer.Flush()
+	return list, content.String(), writer.Error()
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestInactiveMembersCSV(t *testing.T) {
+	since := time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		inactive map[string][]string
+		wantList []string
+		wantCSV  string
+	}{
+		{
+			inactive: map[string][]string{},
+			wantCSV:  "team,github_login,inactive_since\n",
+		},
+		{
+			inactive: map[string][]string{
+				"storage": {"maryk", "johns"},
+				noTeam:    {"jane"},
+				"network": {"johns"},
+			},
+			wantList: []string{
+				"`(no team)`\t1 inactive member/s\n",
+				"`network`\t1 inactive member/s\n",
+				"`storage`\t2 inactive member/s\n",
+			},
+			wantCSV: "team,github_login,inactive_since\n" +
+				"(no team),jane,2024-01-01\n" +
+				"network,johns,2024-01-01\n" +
+				"storage,johns,2024-01-01\n" +
+				"storage,maryk,2024-01-01\n",
+		},
+		{
+			inactive: map[string][]string{"a,b": {"johns"}},
+			wantList: []string{"`a,b`\t1 inactive member/s\n"},
+			wantCSV:  "team,github_login,inactive_since\n\"a,b\",johns,2024-01-01\n",
+		},
+	}
+	for _, tt := range tests {
+		list, content, err := inactiveMembersCSV(tt.inactive, since)
+		if err != nil {
+			t.Errorf("inactiveMembersCSV(%v) error %v", tt.inactive, err)
+			continue
+		}
+		if !reflect.DeepEqual(list, tt.wantList) || content != tt.wantCSV {
+			t.Errorf("inactiveMembersCSV(%v) = %q, %q, want %q, %q", tt.inactive, list, content, tt.wantList, tt.wantCSV)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+	// 2024-01-01 is a Monday
+	from := time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		spec    string
+		want    time.Time
+		wantErr bool
+	}{
+		{spec: "* * * * *", want: time.Date(2024, 1, 1, 10, 31, 0, 0, time.UTC)},
+		{spec: "*/15 * * * *", want: time.Date(2024, 1, 1, 10, 45, 0, 0, time.UTC)},
+		{spec: "0 9 * * 1", want: time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC)},
+		{spec: "0 9 * * 1-5", want: time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)},
+		{spec: "0 9 * * 7", want: time.Date(2024, 1, 7, 9, 0, 0, 0, time.UTC)},
+		{spec: "30 10,12 * * *", want: time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC)},
+		{spec: "0 0 1 * *", want: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 29 2 *", want: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// both day fields restricted, either matches
+		{spec: "0 0 15 * 3", want: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{spec: "5/20 * * * *", want: time.Date(2024, 1, 1, 10, 45, 0, 0, time.UTC)},
+		{spec: "0 0 31 2 *", want: time.Time{}},
+		{spec: "", wantErr: true},
+		{spec: "* * * *", wantErr: true},
+		{spec: "* * * * * *", wantErr: true},
+		{spec: "60 * * * *", wantErr: true},
+		{spec: "* 24 * * *", wantErr: true},
+		{spec: "* * 0 * *", wantErr: true},
+		{spec: "* * * 13 *", wantErr: true},
+		{spec: "* * * * 8", wantErr: true},
+		{spec: "*/0 * * * *", wantErr: true},
+		{spec: "10-5 * * * *", wantErr: true},
+		{spec: "a * * * *", wantErr: true},
+		{spec: "1,,2 * * * *", wantErr: true},
+	}
+	for _, tt := range tests {
+		schedule, err := parseCronSchedule(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseCronSchedule(%q) error %v, want error %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if got := schedule.Next(from); !got.Equal(tt.want) {
+			t.Errorf("parseCronSchedule(%q).Next(%s) = %s, want %s", tt.spec, from, got, tt.want)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/shomali11/slacker"
 	"github.com/slack-go/slack"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -23,20 +24,17 @@ func NewBot(token string) *Bot {
 func (b *Bot) Start() error {
 	bot := slacker.NewClient(os.Getenv("SLACK_BOT_TOKEN"), os.Getenv("SLACK_APP_TOKEN"))
 
-	bot.DefaultCommand(func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
-		err := response.Reply("unrecognized command, msg me `help` for a list of all commands")
-		if err != nil {
-			log.Info().Msg("unrecognized command, msg me `help` for a list of all commands")
-		}
-	})
+	bot.DefaultCommand(defaultHandler)
+
+	// buttons of confirmation messages
+	bot.Interactive(handleInteraction)
 
 	bot.Command("member <action> <github-id> <options>", &slacker.CommandDefinition{
 		Description: fmt.Sprintf("Runs the requested action %s on the github-id with options like team=<team name>) ", strings.Join(codeSlice(supportedMemberActions), ", ")),
-		Example:     "member add johns team=storage",
-		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+		Example:     "1) member add johns team=storage 2) member add johns team=storage;role=maintainer 3) member role johns team=storage role=member 4) member remove johns team=storage 5) member remove johns org=true 6) member import (with a CSV file of github_login,team,role rows)",
+		Handler: forBot(func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
 			var err error
-			githubOrg := os.Getenv("GITHUB_ORG")
-			githubRepo := os.Getenv("GITHUB_REPO")
+			repoTarget := &commandTarget{}
 			//user := botCtx.Event().User
 			channel := botCtx.Event().Channel
 			if !isDirectMessage(channel) {
@@ -56,21 +54,40 @@ func (b *Bot) Start() error {
 				return
 			}
 
+			if action == "import" {
+				// `member import org=other` has no github id, so its options land in the github-id parameter
+				options := strings.Join(strings.Fields(request.StringParam("github-id", "")+" "+request.StringParam("options", "")), ";")
+				if rest := repoTarget.takeOptions(options); len(rest) > 0 {
+					response.Reply(fmt.Sprintf("`member import` only accepts `org=<name>`, got `%s`", rest)) //nolint:errcheck
+					return
+				}
+				importMembers(botCtx, repoTarget, response)
+				return
+			}
+
 			user := request.StringParam("github-id", "")
 			log.Debug().Str("github-id", user).Msg("Received github id ")
 			if len(user) == 0 || len(strings.Fields(user)) > 1 {
 				response.Reply("You must specify a user") //nolint:errcheck
 				return
 			}
-			params, err := parseOptions(request.StringParam("options", ""), supportedMemberOptions)
+			user, err = resolveLogin(user, botCtx.Event().User)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			// member options have no spaces in their values, so
+			// `team=x role=maintainer` is accepted as well as `team=x;role=maintainer`
+			options := strings.Join(strings.Fields(request.StringParam("options", "")), ";")
+			params, err := parseOptions(repoTarget.takeOptions(options), supportedMemberOptions)
 			if err != nil {
 				response.Reply(err.Error())
 				return
 			}
 
-			if len(params["team"]) > 0 {
-				if contains(params["team"], "admin") || contains(params["team"], "Admin") {
-					response.Reply("You are not privileged to update `admin` team") //nolint:errcheck
+			for _, team := range params["team"] {
+				if isProtectedTeam(team) {
+					response.Reply(fmt.Sprintf("You are not privileged to update `%s` team", team)) //nolint:errcheck
 					return
 				}
 			}
@@ -82,11 +99,42 @@ func (b *Bot) Start() error {
 				UserName: user,
 				Action:   action,
 				Team:     strings.Join(params["team"], ","),
+				Role:     strings.TrimSpace(strings.Join(params["role"], "")),
 			}
-			githubAct := GithubActions{
-				Organization: githubOrg,
-				Repository:   githubRepo,
-				Member:       memAct,
+			if len(params["org"]) > 0 {
+				memAct.FromOrg, err = strconv.ParseBool(strings.TrimSpace(params["org"][0]))
+				if err != nil {
+					response.Reply("org must be `true` or `false`")
+					return
+				}
+			}
+			githubAct, err := repoTarget.resolveOrg()
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			githubAct.Member = memAct
+			if action == "remove" {
+				msg, teamList, err := githubAct.memberRemovalPreview()
+				if err != nil {
+					response.Reply(err.Error())
+					return
+				}
+				err = askConfirmation(botCtx.Client(), channel, msg, teamList, &confirmation{
+					Requester: botCtx.Event().User,
+					Summary:   fmt.Sprintf("removal of %s (team=%s, org=%t) from %s", user, memAct.Team, memAct.FromOrg, githubAct.Organization),
+					Run: func(confirmedBy string) string {
+						msg, err := githubAct.removeMember(confirmedBy)
+						if err != nil {
+							return err.Error()
+						}
+						return msg
+					},
+				})
+				if err != nil {
+					response.Reply(err.Error())
+				}
+				return
 			}
 			status, msg, err := githubAct.actOnMember()
 			if status {
@@ -95,19 +143,80 @@ func (b *Bot) Start() error {
 			} else {
 				response.Reply(err.Error())
 			}
-		},
+		}),
+	})
+
+	bot.Command("link <github-login>", &slacker.CommandDefinition{
+		Description: "Links your Slack account to your GitHub account, once linked `me` stands for your GitHub login in commands",
+		Example:     "link johns",
+		Handler: forBot(func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			if !isDirectMessage(botCtx.Event().Channel) {
+				err := response.Reply("this command is only accepted via direct message")
+				if err != nil {
+					log.Info().Msg("this command is only accepted via direct message")
+				}
+				return
+			}
+			login := strings.TrimSpace(request.StringParam("github-login", ""))
+			if len(login) == 0 || len(strings.Fields(login)) > 1 || strings.EqualFold(login, "me") {
+				response.Reply("You must specify your GitHub login")
+				return
+			}
+			msg, err := linkAccount(botCtx.Event().User, login)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			response.Reply(msg)
+		}),
+	})
+
+	bot.Command("unlink", &slacker.CommandDefinition{
+		Description: "Removes the link between your Slack account and your GitHub account",
+		Handler: forBot(func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			if !isDirectMessage(botCtx.Event().Channel) {
+				err := response.Reply("this command is only accepted via direct message")
+				if err != nil {
+					log.Info().Msg("this command is only accepted via direct message")
+				}
+				return
+			}
+			msg, err := unlinkAccount(botCtx.Event().User)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			response.Reply(msg)
+		}),
+	})
+
+	bot.Command("whoami", &slacker.CommandDefinition{
+		Description: "Shows the GitHub account linked to your Slack account",
+		Handler: forBot(func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			if !isDirectMessage(botCtx.Event().Channel) {
+				err := response.Reply("this command is only accepted via direct message")
+				if err != nil {
+					log.Info().Msg("this command is only accepted via direct message")
+				}
+				return
+			}
+			msg, err := whoami(botCtx.Event().User)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			response.Reply(msg)
+		}),
 	})
 
-	bot.Command("team <action>", &slacker.CommandDefinition{
+	bot.Command("team <action?> <slug?> <options>", &slacker.CommandDefinition{
 		Description: fmt.Sprintf("Run the requested action %s ", strings.Join(codeSlice(supportedTeamActions), ", ")),
-		Example:     "1) team list ",
-		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+		Example:     "1) team list 2) team list org=<org> 3) team members storage 4) team show storage 5) team create storage description=Storage team;privacy=closed;parent=eng;maintainers=johns;jane 6) team delete storage 7) team repos storage 8) team grant storage repo=backend permission=push 9) team revoke storage repo=backend 10) team join storage reason=on call for the storage service",
+		Handler: forBot(func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
 			var err error
-			githubOrg := os.Getenv("GITHUB_ORG")
-			githubRepo := os.Getenv("GITHUB_REPO")
+			repoTarget := &commandTarget{}
 			//user := botCtx.Event().User
 			channel := botCtx.Event().Channel
-			attachments := []slack.Attachment{}
 			if !isDirectMessage(channel) {
 				err := response.Reply("this command is only accepted via direct message")
 				if err != nil {
@@ -124,74 +233,788 @@ func (b *Bot) Start() error {
 				response.Reply("you must specify what action need to be taken")
 				return
 			}
+			slug := strings.TrimSpace(repoTarget.takeOptions(request.StringParam("slug", "")))
+			options := request.StringParam("options", "")
+			if action == "grant" || action == "revoke" {
+				// grant options have no spaces in their values, so
+				// `repo=x permission=push` is accepted as well as `repo=x;permission=push`
+				options = strings.Join(strings.Fields(options), ";")
+			}
+			options = repoTarget.takeOptions(options)
 
 			TeamAct := &TeamAction{
 				Action: action,
+				Slug:   slug,
 			}
-			githubAct := GithubActions{
-				Organization: githubOrg,
-				Repository:   githubRepo,
-				Team:         TeamAct,
+			if action == "create" {
+				params, err := parseOptions(joinListOptions(options, "maintainers"), supportedTeamCreateOptions)
+				if err != nil {
+					response.Reply(err.Error())
+					return
+				}
+				TeamAct.Description = strings.TrimSpace(strings.Join(params["description"], ""))
+				TeamAct.Privacy = strings.TrimSpace(strings.Join(params["privacy"], ""))
+				TeamAct.Parent = strings.TrimSpace(strings.Join(params["parent"], ""))
+				TeamAct.Maintainers = splitOptionValues(params["maintainers"])
+			} else if action == "join" {
+				params, err := parseOptions(options, supportedTeamJoinOptions)
+				if err != nil {
+					response.Reply(err.Error())
+					return
+				}
+				TeamAct.Reason = strings.TrimSpace(strings.Join(params["reason"], ""))
+			} else if action == "grant" || action == "revoke" {
+				params, err := parseOptions(options, supportedTeamGrantOptions)
+				if err != nil {
+					response.Reply(err.Error())
+					return
+				}
+				TeamAct.Permission = strings.TrimSpace(strings.Join(params["permission"], ""))
+			} else if len(options) > 0 {
+				response.Reply(fmt.Sprintf("unrecognized option: %s", options))
+				return
+			}
+			var githubAct GithubActions
+			if action == "grant" || action == "revoke" {
+				// the repository must be named, channel defaults do not apply
+				if len(repoTarget.Repository) == 0 {
+					response.Reply(fmt.Sprintf("`%s` expects repo=<repository> as input", action))
+					return
+				}
+				githubAct, err = repoTarget.resolve(channel, botCtx.Event().User)
+			} else {
+				githubAct, err = repoTarget.resolveOrg()
+			}
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			githubAct.Team = TeamAct
+			if action == "join" {
+				requestTeamJoin(botCtx, githubAct, response)
+				return
+			}
+			if action == "delete" {
+				msg, details, err := githubAct.teamDeletionPreview()
+				if err != nil {
+					response.Reply(err.Error())
+					return
+				}
+				err = askConfirmation(botCtx.Client(), channel, msg, details, &confirmation{
+					Requester: botCtx.Event().User,
+					Summary:   fmt.Sprintf("deletion of team %s from %s", slug, githubAct.Organization),
+					Run: func(confirmedBy string) string {
+						msg, err := githubAct.deleteTeam(confirmedBy)
+						if err != nil {
+							return err.Error()
+						}
+						return msg
+					},
+				})
+				if err != nil {
+					response.Reply(err.Error())
+				}
+				return
 			}
 
 			status, teamList, msg, err := githubAct.actOnTeam()
 			if status {
-				if len(teamList) != 0 {
-					var list string
-					response.Reply(msg)
-					count := 60
-					for i := 0; i < len(teamList); i++ {
-						attachments = []slack.Attachment{}
-						if i < count {
-							list = list + fmt.Sprintf(fmt.Sprintf(teamList[i]))
-
-						} else {
-							attachments = append(attachments, slack.Attachment{
-								ID:   count,
-								Text: list,
-							})
-							log.Debug().Msg(fmt.Sprintf("list : %s", list))
-							response.Reply("", slacker.WithAttachments(attachments))
-							count = count + 60
-							list = ""
-							i = i - 1
-						}
+				replyWithList(response, msg, teamList)
+				return
+			} else {
+				response.Reply(err.Error())
+			}
+		}),
+	})
+
+	bot.Command("invite <action?> <invitee?> <options>", &slacker.CommandDefinition{
+		Description: fmt.Sprintf("Runs the requested action %s on the pending invitations of the organization", strings.Join(codeSlice(supportedInviteActions), ", ")),
+		Example:     "1) invite list 2) invite cancel johns 3) invite resend johns 4) invite email john@example.com team=storage",
+		Handler: forBot(func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			repoTarget := &commandTarget{}
+			channel := botCtx.Event().Channel
+			if !isDirectMessage(channel) {
+				err := response.Reply("this command is only accepted via direct message")
+				if err != nil {
+					log.Info().Msg("this command is only accepted via direct message")
+				}
+				return
+			}
+			action, err := parseActions(request.StringParam("action", ""), supportedInviteActions)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			invitee := unwrapSlackLink(strings.TrimSpace(repoTarget.takeOptions(request.StringParam("invitee", ""))))
+			params, err := parseOptions(repoTarget.takeOptions(request.StringParam("options", "")), supportedInviteOptions)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			inviteAct := &InviteAction{
+				Action:  action,
+				Invitee: invitee,
+				Team:    strings.TrimSpace(strings.Join(params["team"], "")),
+			}
+			githubAct, err := repoTarget.resolveOrg()
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			githubAct.Invite = inviteAct
+			status, inviteList, msg, err := githubAct.actOnInvite()
+			if status {
+				replyWithList(response, msg, inviteList)
+				return
+			} else {
+				response.Reply(err.Error())
+			}
+		}),
+	})
+
+	bot.Command("collaborator <action?> <login?> <options>", &slacker.CommandDefinition{
+		Description: fmt.Sprintf("Runs the requested action %s on the direct collaborators of a repository, `audit` reports the outside collaborators with write access or more in the organization", strings.Join(codeSlice(supportedCollaboratorActions), ", ")),
+		Example:     "1) collaborator list repo=backend 2) collaborator add johns repo=backend permission=triage 3) collaborator remove johns repo=backend 4) collaborator audit",
+		Handler: forBot(func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			repoTarget := &commandTarget{}
+			channel := botCtx.Event().Channel
+			if !isDirectMessage(channel) {
+				err := response.Reply("this command is only accepted via direct message")
+				if err != nil {
+					log.Info().Msg("this command is only accepted via direct message")
+				}
+				return
+			}
+			action, err := parseActions(request.StringParam("action", ""), supportedCollaboratorActions)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			if len(action) == 0 {
+				response.Reply("you must specify what action need to be taken")
+				return
+			}
+			login, err := resolveLogin(strings.TrimSpace(repoTarget.takeOptions(request.StringParam("login", ""))), botCtx.Event().User)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			// collaborator options have no spaces in their values, so
+			// `repo=x permission=push` is accepted as well as `repo=x;permission=push`
+			options := strings.Join(strings.Fields(request.StringParam("options", "")), ";")
+			params, err := parseOptions(repoTarget.takeOptions(options), supportedCollaboratorOptions)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			collaboratorAct := &CollaboratorAction{
+				Action:     action,
+				Login:      login,
+				Permission: strings.TrimSpace(strings.Join(params["permission"], "")),
+			}
+			if action == "add" && len(collaboratorAct.Permission) == 0 {
+				collaboratorAct.Permission = "push"
+			}
+			var githubAct GithubActions
+			if action == "audit" {
+				githubAct, err = repoTarget.resolveOrg()
+			} else if len(repoTarget.Repository) == 0 {
+				// access is changed on a named repository only, defaults do not apply
+				response.Reply(fmt.Sprintf("`%s` expects repo=<repository> as input", action))
+				return
+			} else {
+				githubAct, err = repoTarget.resolve(channel, botCtx.Event().User)
+			}
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			githubAct.Collaborator = collaboratorAct
+			if action == "audit" {
+				response.Reply(fmt.Sprintf("checking every repository of `%s`, this may take a while", githubAct.Organization))
+			}
+			status, collaboratorList, msg, err := githubAct.actOnCollaborator()
+			if status {
+				replyWithList(response, msg, collaboratorList)
+				return
+			} else {
+				response.Reply(err.Error())
+			}
+		}),
+	})
+
+	bot.Command("report <type?> <options>", &slacker.CommandDefinition{
+		Description: fmt.Sprintf("Runs the requested report %s on the organization", strings.Join(codeSlice(supportedReportTypes), ", ")),
+		Example:     "1) report inactive 2) report inactive days=180 org=<org> 3) report 2fa",
+		Handler: forBot(func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			repoTarget := &commandTarget{}
+			channel := botCtx.Event().Channel
+			if !isDirectMessage(channel) {
+				err := response.Reply("this command is only accepted via direct message")
+				if err != nil {
+					log.Info().Msg("this command is only accepted via direct message")
+				}
+				return
+			}
+			reportType, err := parseActions(repoTarget.takeOptions(request.StringParam("type", "")), supportedReportTypes)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			if len(reportType) == 0 {
+				response.Reply(fmt.Sprintf("you must specify the report, one of %s", strings.Join(codeSlice(supportedReportTypes), ", ")))
+				return
+			}
+			params, err := parseOptions(repoTarget.takeOptions(request.StringParam("options", "")), supportedReportOptions)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			reportAct := &ReportAction{
+				Type: reportType,
+				Days: defaultInactiveDays,
+			}
+			if days := strings.TrimSpace(strings.Join(params["days"], "")); len(days) > 0 {
+				reportAct.Days, err = strconv.Atoi(days)
+				if err != nil {
+					response.Reply(fmt.Sprintf("`%s` is not a valid number of days", days))
+					return
+				}
+			}
+			githubAct, err := repoTarget.resolveOrg()
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			githubAct.Report = reportAct
+			response.Reply(fmt.Sprintf("building the `%s` report of `%s`, this may take a while", reportType, githubAct.Organization))
+			status, result, err := githubAct.actOnReport()
+			if !status {
+				response.Reply(err.Error())
+				return
+			}
+			replyWithList(response, result.Summary, result.List)
+			if len(result.CSV) > 0 {
+				err = uploadCSV(botCtx.Client(), channel, result.Filename, "", result.CSV)
+				if err != nil {
+					response.Reply(fmt.Sprintf("unable to upload `%s`, Error: %s", result.Filename, err))
+				}
+			}
+		}),
+	})
+
+	bot.Command("issue <action?> <state-or-id?> <options>", &slacker.CommandDefinition{
+		Description: fmt.Sprintf("Runs the requested action %s on the issues of the repository. `list` supports the states %s and options %s", strings.Join(codeSlice(supportedIssueActions), ", "), strings.Join(codeSlice(supportedIssueStates), ", "), strings.Join(codeSlice(supportedIssueOptions), ", ")),
+		Example:     "1) issue list assignedto username=johns;noupdatesince=2022-01-01 2) issue create title=Fix build;labels=bug;assignees=johns 3) issue comment 12 looking into it 4) issue close 12 reason=not_planned 5) issue assign 12 users=johns,jane 6) issue list open repo=other-repo",
+		Handler: forBot(func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			repoTarget := &commandTarget{}
+			action, err := parseActions(request.StringParam("action", ""), supportedIssueActions)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			stateOrID := repoTarget.takeOptions(request.StringParam("state-or-id", ""))
+			options := repoTarget.takeOptions(request.StringParam("options", ""))
+			issueAct := &IssueAction{
+				Action: action,
+			}
+			switch action {
+			case "create":
+				// create has no state or id, the whole text is options
+				// `labels=bug;urgent` lists like `team create maintainers=a;b`
+				createOptions := joinListOptions(joinListOptions(strings.TrimSpace(stateOrID+" "+options), "labels"), "assignees")
+				params, err := parseOptions(createOptions, supportedIssueCreateOptions)
+				if err != nil {
+					response.Reply(err.Error())
+					return
+				}
+				issueAct.Title = strings.TrimSpace(strings.Join(params["title"], ""))
+				issueAct.Body = strings.TrimSpace(strings.Join(params["body"], ""))
+				issueAct.Labels = splitOptionValues(params["labels"])
+				issueAct.Assignees = splitOptionValues(params["assignees"])
+			case "list":
+				state, _, err := parseIssueState(stateOrID)
+				if err != nil {
+					response.Reply(err.Error())
+					return
+				}
+				if !contains(supportedIssueStates, state) {
+					response.Reply(fmt.Sprintf("unknown state `%s`, supported states are %s", state, strings.Join(codeSlice(supportedIssueStates), ", ")))
+					return
+				}
+				params, err := parseOptions(options, supportedIssueOptions)
+				if err != nil {
+					response.Reply(err.Error())
+					return
+				}
+				issueAct.State = state
+				issueAct.UserName = strings.Join(params["username"], "")
+				issueAct.LastUpdated = strings.Join(params["noupdatesince"], "")
+				issueAct.Labels = params["label"]
+			default:
+				_, issueAct.Number, err = parseIssueState(stateOrID)
+				if err != nil || issueAct.Number == 0 {
+					response.Reply(fmt.Sprintf("`%s` expects an issue number, msg me `help` for example", action))
+					return
+				}
+				switch action {
+				case "comment":
+					issueAct.Body = strings.TrimSpace(options)
+				case "close":
+					params, err := parseOptions(options, supportedIssueCloseOptions)
+					if err != nil {
+						response.Reply(err.Error())
+						return
+					}
+					issueAct.Reason = strings.TrimSpace(strings.Join(params["reason"], ""))
+				case "assign", "unassign":
+					params, err := parseOptions(joinListOptions(options, "users"), supportedIssueAssignOptions)
+					if err != nil {
+						response.Reply(err.Error())
+						return
 					}
-					attachments = append(attachments, slack.Attachment{
-						ID:   count,
-						Text: list,
-					})
-					log.Debug().Msg(fmt.Sprintf("list : %s", list))
-					response.Reply("", slacker.WithAttachments(attachments))
+					issueAct.Assignees = splitOptionValues(params["users"])
+				}
+			}
+			// `me` is the GitHub login linked to the requester
+			issueAct.UserName, err = resolveLogin(issueAct.UserName, botCtx.Event().User)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			issueAct.Assignees, err = resolveLogins(issueAct.Assignees, botCtx.Event().User)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			githubAct, err := repoTarget.resolve(botCtx.Event().Channel, botCtx.Event().User)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			issueAct.Requester = botCtx.Event().User
+			if action == "create" || action == "comment" {
+				issueAct.RequesterName = requesterName(botCtx.Client(), botCtx.Event().User)
+			}
+			githubAct.Issue = issueAct
+			status, issueList, msg, err := githubAct.actOnIssue()
+			if status {
+				replyWithList(response, msg, issueList)
+				return
+			} else {
+				response.Reply(err.Error())
+			}
+		}),
+	})
 
-				} else {
-					attachments = []slack.Attachment{}
+	bot.Command("label <action?> <issue?> <options>", &slacker.CommandDefinition{
+		Description: fmt.Sprintf("Runs the requested action %s on the labels of the repository with options %s, `create` takes %s", strings.Join(codeSlice(supportedLabelActions), ", "), strings.Join(codeSlice(supportedLabelOptions), ", "), strings.Join(codeSlice(supportedLabelCreateOptions), ", ")),
+		Example:     "1) label add 12 labels=bug;triage 2) label list 3) label create name=triage;color=fbca04;description=Needs triage",
+		Handler: forBot(func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			repoTarget := &commandTarget{}
+			action, err := parseActions(request.StringParam("action", ""), supportedLabelActions)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			labelAct := &LabelAction{
+				Action: action,
+			}
+			issue := repoTarget.takeOptions(request.StringParam("issue", ""))
+			options := repoTarget.takeOptions(request.StringParam("options", ""))
+			switch action {
+			case "create":
+				// create has no issue number, the whole text is options
+				params, err := parseOptions(strings.TrimSpace(issue+" "+options), supportedLabelCreateOptions)
+				if err != nil {
+					response.Reply(err.Error())
+					return
+				}
+				labelAct.Name = strings.TrimSpace(strings.Join(params["name"], ""))
+				labelAct.Color = strings.TrimPrefix(strings.TrimSpace(strings.Join(params["color"], "")), "#")
+				labelAct.Description = strings.TrimSpace(strings.Join(params["description"], ""))
+			default:
+				if len(issue) > 0 {
+					_, labelAct.IssueNumber, err = parseIssueState(issue)
+					if err != nil || labelAct.IssueNumber == 0 {
+						response.Reply(fmt.Sprintf("`%s` is not a valid issue number", issue))
+						return
+					}
+				}
+				if action == "add" || action == "remove" {
+					labelAct.Labels, err = parseLabelNames(options)
+					if err != nil {
+						response.Reply(err.Error())
+						return
+					}
+				}
+			}
+			githubAct, err := repoTarget.resolve(botCtx.Event().Channel, botCtx.Event().User)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			githubAct.Label = labelAct
+			status, labelList, msg, err := githubAct.actOnLabel()
+			if status {
+				replyWithList(response, msg, labelList)
+				return
+			} else {
+				response.Reply(err.Error())
+			}
+		}),
+	})
 
-					attachments = append(attachments, slack.Attachment{
-						ID:   1,
-						Text: msg,
-					})
-					response.Reply("", slacker.WithAttachments(attachments))
+	bot.Command("pr <action?> <number?> <options>", &slacker.CommandDefinition{
+		Description: fmt.Sprintf("Runs the requested action %s on the pull requests of the repository. `list` supports the options %s", strings.Join(codeSlice(supportedPullActions), ", "), strings.Join(codeSlice(supportedPullOptions), ", ")),
+		Example:     "1) pr list state=open;author=johns;label=bug;reviewer=jane 2) pr show 42 3) pr reviews 42 4) pr merge 42 method=squash 5) pr show 42 repo=other-org/other-repo",
+		Handler: forBot(func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			repoTarget := &commandTarget{}
+			action, err := parseActions(request.StringParam("action", ""), supportedPullActions)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			number := repoTarget.takeOptions(request.StringParam("number", ""))
+			options := repoTarget.takeOptions(request.StringParam("options", ""))
+			pullAct := &PullAction{
+				Action: action,
+			}
+			if action == "list" {
+				// list has no number, the whole text is options
+				params, err := parseOptions(strings.TrimSpace(number+" "+options), supportedPullOptions)
+				if err != nil {
+					response.Reply(err.Error())
+					return
+				}
+				pullAct.State = strings.TrimSpace(strings.Join(params["state"], ""))
+				pullAct.Author = strings.TrimSpace(strings.Join(params["author"], ""))
+				pullAct.Labels = splitOptionValues(params["label"])
+				pullAct.Reviewer = strings.TrimSpace(strings.Join(params["reviewer"], ""))
+				// `me` is the GitHub login linked to the requester
+				pullAct.Author, err = resolveLogin(pullAct.Author, botCtx.Event().User)
+				if err != nil {
+					response.Reply(err.Error())
+					return
+				}
+				pullAct.Reviewer, err = resolveLogin(pullAct.Reviewer, botCtx.Event().User)
+				if err != nil {
+					response.Reply(err.Error())
+					return
+				}
+			} else {
+				_, pullAct.Number, err = parseIssueState(number)
+				if err != nil || pullAct.Number == 0 {
+					response.Reply(fmt.Sprintf("`%s` expects a pull request number, msg me `help` for example", action))
+					return
+				}
+				if action == "merge" {
+					params, err := parseOptions(options, supportedPullMergeOptions)
+					if err != nil {
+						response.Reply(err.Error())
+						return
+					}
+					pullAct.Method = strings.TrimSpace(strings.Join(params["method"], ""))
+					pullAct.Requester = botCtx.Event().User
+				}
+			}
+			githubAct, err := repoTarget.resolve(botCtx.Event().Channel, botCtx.Event().User)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			githubAct.Pull = pullAct
+			status, pullList, msg, err := githubAct.actOnPull()
+			if status {
+				replyWithList(response, msg, pullList)
+				return
+			} else {
+				response.Reply(err.Error())
+			}
+		}),
+	})
 
+	bot.Command("workflow <action?> <target?> <options>", &slacker.CommandDefinition{
+		Description: fmt.Sprintf("Runs the requested action %s on the GitHub Actions workflows of the repository", strings.Join(codeSlice(supportedWorkflowActions), ", ")),
+		Example:     "1) workflow list 2) workflow run build.yml ref=main;inputs.version=1.2.0 3) workflow status 123456789",
+		Handler: forBot(func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			repoTarget := &commandTarget{}
+			action, err := parseActions(request.StringParam("action", ""), supportedWorkflowActions)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			target := strings.TrimSpace(repoTarget.takeOptions(request.StringParam("target", "")))
+			workflowAct := &WorkflowAction{
+				Action: action,
+			}
+			switch action {
+			case "run":
+				workflowAct.File = target
+				workflowAct.Ref, workflowAct.Inputs, err = parseWorkflowOptions(repoTarget.takeOptions(request.StringParam("options", "")))
+				if err != nil {
+					response.Reply(err.Error())
+					return
 				}
+			case "status":
+				workflowAct.RunID, err = strconv.ParseInt(target, 10, 64)
+				if err != nil {
+					response.Reply(fmt.Sprintf("`%s` is not a valid run id", target))
+					return
+				}
+			}
+			githubAct, err := repoTarget.resolve(botCtx.Event().Channel, botCtx.Event().User)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			githubAct.Workflow = workflowAct
+			if action == "run" {
+				if !isWorkflowRunAllowed(botCtx.Event().User) {
+					response.Reply(fmt.Sprintf("<@%s> is not allowed to run workflows", botCtx.Event().User))
+					return
+				}
+				if stat, err := githubAct.validateInputs(); !stat {
+					response.Reply(err.Error())
+					return
+				}
+				channel := botCtx.Event().Channel
+				err = askConfirmation(botCtx.Client(), channel, githubAct.workflowRunPreview(), nil, &confirmation{
+					Requester: botCtx.Event().User,
+					Summary:   fmt.Sprintf("run of workflow %s in %s/%s", workflowAct.File, githubAct.Organization, githubAct.Repository),
+					Run: func(confirmedBy string) string {
+						run, err := githubAct.runWorkflow()
+						if err != nil {
+							return err.Error()
+						}
+						go githubAct.followWorkflowRun(botCtx.Client(), channel, run)
+						return fmt.Sprintf("run `%d` started", run.GetID())
+					},
+				})
+				if err != nil {
+					response.Reply(err.Error())
+				}
+				return
+			}
+			status, workflowList, msg, err := githubAct.actOnWorkflow()
+			if status {
+				replyWithList(response, msg, workflowList)
 				return
 			} else {
 				response.Reply(err.Error())
 			}
-		},
+		}),
+	})
+
+	bot.Command("ci <action?> <target?> <options>", &slacker.CommandDefinition{
+		Description: fmt.Sprintf("Runs the requested action %s on the GitHub Actions runs of a branch, pull request or run", strings.Join(codeSlice(supportedCIActions), ", ")),
+		Example:     "1) ci failures main 2) ci failures 42 3) ci rerun 123456789 failed-only=true",
+		Handler: forBot(func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+			repoTarget := &commandTarget{}
+			action, err := parseActions(request.StringParam("action", ""), supportedCIActions)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			target := strings.TrimSpace(repoTarget.takeOptions(request.StringParam("target", "")))
+			ciAct := &CIAction{
+				Action: action,
+			}
+			switch action {
+			case "failures":
+				// a number is a pull request, anything else a branch
+				_, ciAct.PullNumber, err = parseIssueState(target)
+				if err != nil {
+					response.Reply(err.Error())
+					return
+				}
+				if ciAct.PullNumber == 0 {
+					ciAct.Branch = target
+				}
+			case "rerun":
+				ciAct.RunID, err = strconv.ParseInt(target, 10, 64)
+				if err != nil {
+					response.Reply(fmt.Sprintf("`%s` is not a valid run id", target))
+					return
+				}
+				params, err := parseOptions(repoTarget.takeOptions(request.StringParam("options", "")), supportedCIRerunOptions)
+				if err != nil {
+					response.Reply(err.Error())
+					return
+				}
+				if len(params["failed-only"]) > 0 {
+					ciAct.FailedOnly, err = strconv.ParseBool(strings.TrimSpace(params["failed-only"][0]))
+					if err != nil {
+						response.Reply("failed-only must be `true` or `false`")
+						return
+					}
+				}
+			}
+			githubAct, err := repoTarget.resolve(botCtx.Event().Channel, botCtx.Event().User)
+			if err != nil {
+				response.Reply(err.Error())
+				return
+			}
+			githubAct.CI = ciAct
+			if action == "rerun" {
+				// a re-run can deploy as much as `workflow run`
+				if !isWorkflowRunAllowed(botCtx.Event().User) {
+					response.Reply(fmt.Sprintf("<@%s> is not allowed to re-run workflows", botCtx.Event().User))
+					return
+				}
+				preview, err := githubAct.rerunPreview()
+				if err != nil {
+					response.Reply(err.Error())
+					return
+				}
+				err = askConfirmation(botCtx.Client(), botCtx.Event().Channel, preview, nil, &confirmation{
+					Requester: botCtx.Event().User,
+					Summary:   fmt.Sprintf("re-run of run %d in %s/%s", ciAct.RunID, githubAct.Organization, githubAct.Repository),
+					Run: func(confirmedBy string) string {
+						_, _, msg, err := githubAct.actOnCI()
+						if err != nil {
+							return err.Error()
+						}
+						return msg
+					},
+				})
+				if err != nil {
+					response.Reply(err.Error())
+				}
+				return
+			}
+			status, failureList, msg, err := githubAct.actOnCI()
+			if status {
+				replyWithList(response, msg, failureList)
+				return
+			} else {
+				response.Reply(err.Error())
+			}
+		}),
 	})
 
 	bot.Command("version", &slacker.CommandDefinition{
 		Description: "Report the version of the bot",
-		Handler: func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+		Handler: forBot(func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
 			err := response.Reply(fmt.Sprintf("Running from https://github.com/sudeeshjohn/github-slack-bot"))
 			if err != nil {
 				log.Info().Msg("Unable to send the slack message")
 			}
-		},
+		}),
 	})
 
-	return bot.Listen(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	startStaleIssueDigest(ctx, bot.Client())
+	startTwoFactorReminders(ctx, bot.Client())
+	startOffboardingWatch(ctx, bot.Client())
+
+	return bot.Listen(ctx)
+}
+
+// defaultHandler unfurls the GitHub links of messages that are not a
+// command.
+func defaultHandler(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+	links := parseGithubLinks(botCtx.Event().Text)
+	if len(links) > 0 {
+		unfurlLinks(response, links)
+		return
+	}
+	// stay quiet on channel messages that are not meant for the bot
+	if !isForBot(botCtx) {
+		return
+	}
+	err := response.Reply("unrecognized command, msg me `help` for a list of all commands")
+	if err != nil {
+		log.Info().Msg("unrecognized command, msg me `help` for a list of all commands")
+	}
+}
+
+// isForBot reports whether the message is a direct message or mentions the
+// bot. Slacker matches a command anywhere in a message, so "see the pr
+// <link>" in a channel would otherwise run `pr`.
+func isForBot(botCtx slacker.BotContext) bool {
+	return isDirectMessage(botCtx.Event().Channel) || botCtx.Event().Type == "app_mention"
+}
+
+// forBot runs the command handler for direct messages and mentions only,
+// other channel messages go to defaultHandler so their links are unfurled.
+func forBot(handler func(slacker.BotContext, slacker.Request, slacker.ResponseWriter)) func(slacker.BotContext, slacker.Request, slacker.ResponseWriter) {
+	return func(botCtx slacker.BotContext, request slacker.Request, response slacker.ResponseWriter) {
+		if !isForBot(botCtx) {
+			defaultHandler(botCtx, request, response)
+			return
+		}
+		handler(botCtx, request, response)
+	}
+}
+
+// replyWithList replies with msg followed by the list items, split across
+// attachments of 60 lines each to stay under Slack's message size limit.
+func replyWithList(response slacker.ResponseWriter, msg string, list []string) {
+	if len(list) == 0 {
+		attachments := []slack.Attachment{{
+			ID:   1,
+			Text: msg,
+		}}
+		response.Reply("", slacker.WithAttachments(attachments))
+		return
+	}
+	response.Reply(msg)
+	for i, text := range chunkList(list, 60) {
+		attachments := []slack.Attachment{{
+			ID:   (i + 1) * 60,
+			Text: text,
+		}}
+		log.Debug().Msg(fmt.Sprintf("list : %s", text))
+		response.Reply("", slacker.WithAttachments(attachments))
+	}
+}
+
+// postWithList is replyWithList for messages that are not a reply to a
+// command, like scheduled reports.
+func postWithList(client *slack.Client, channel string, msg string, list []string) error {
+	_, _, err := client.PostMessage(channel, slack.MsgOptionText(msg, false))
+	if err != nil {
+		return err
+	}
+	for i, text := range chunkList(list, 60) {
+		attachments := []slack.Attachment{{
+			ID:   (i + 1) * 60,
+			Text: text,
+		}}
+		_, _, err = client.PostMessage(channel, slack.MsgOptionAttachments(attachments...))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// uploadCSV shares content as a CSV file in the channel.
+func uploadCSV(client *slack.Client, channel string, filename string, comment string, content string) error {
+	_, err := client.UploadFile(slack.FileUploadParameters{
+		Content:        content,
+		Filetype:       "csv",
+		Filename:       filename,
+		Title:          filename,
+		InitialComment: comment,
+		Channels:       []string{channel},
+	})
+	return err
+}
+
+// chunkList joins the list items into chunks of at most size items.
+func chunkList(list []string, size int) []string {
+	var chunks []string
+	for start := 0; start < len(list); start += size {
+		end := start + size
+		if end > len(list) {
+			end = len(list)
+		}
+		chunks = append(chunks, strings.Join(list[start:end], ""))
+	}
+	return chunks
 }
 
 func isDirectMessage(channel string) bool {
@@ -259,11 +1082,81 @@ func paramsFromAnnotation(str string) (map[string][]string, error) {
 	}
 	return values, nil
 }
+
+// splitOptionValues splits comma separated option values like
+// `assignees=a,b` into their individual items.
+func splitOptionValues(values []string) []string {
+	var items []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); len(item) > 0 {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// requesterName names the Slack user in text the bot posts to GitHub for
+// them: the linked GitHub login, else the Slack display name.
+func requesterName(client *slack.Client, slackUser string) string {
+	if login, err := linkedLogin(slackUser); err == nil && len(login) > 0 {
+		return "@" + login
+	}
+	user, err := client.GetUserInfo(slackUser)
+	if err != nil {
+		log.Info().Msg(fmt.Sprintf("Unable to get the Slack user %s, Error: %s", slackUser, err))
+		return fmt.Sprintf("Slack user %s", slackUser)
+	}
+	if len(user.Profile.DisplayName) > 0 {
+		return fmt.Sprintf("%s on Slack", user.Profile.DisplayName)
+	}
+	return fmt.Sprintf("%s on Slack", user.RealName)
+}
+
+// joinListOptions rewrites a list option written as `key=a;b` to `key=a,b`,
+// the parts after it without a `=` are values of the list.
+func joinListOptions(options string, key string) string {
+	var parts []string
+	inList := false
+	for _, part := range strings.Split(options, ";") {
+		switch {
+		case strings.HasPrefix(strings.TrimSpace(part), key+"="):
+			inList = true
+		case inList && !strings.Contains(part, "=") && len(strings.TrimSpace(part)) > 0:
+			parts[len(parts)-1] = parts[len(parts)-1] + "," + strings.TrimSpace(part)
+			continue
+		default:
+			inList = false
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ";")
+}
+
+// unwrapSlackLink returns the text of a value Slack turned into a link, like
+// `<mailto:john@example.com|john@example.com>` for an email address.
+func unwrapSlackLink(value string) string {
+	if !strings.HasPrefix(value, "<") || !strings.HasSuffix(value, ">") {
+		return value
+	}
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "<"), ">")
+	if i := strings.Index(value, "|"); i >= 0 {
+		return value[i+1:]
+	}
+	return strings.TrimPrefix(value, "mailto:")
+}
 func parseIssueState(stateOrID string) (string, int, error) {
 	if len(stateOrID) == 0 || len(strings.Fields(stateOrID)) > 1 {
 		return "", 0, fmt.Errorf("state/id must not be empty or many. msg me `help` for more information")
 	}
 	stat := strings.TrimSpace(stateOrID)
+	if id, err := strconv.Atoi(strings.TrimPrefix(stat, "#")); err == nil {
+		if id <= 0 {
+			return "", 0, fmt.Errorf("invalid issue number `%s`", stat)
+		}
+		return "", id, nil
+	}
 	return stat, 0, nil
 }
 
@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestJoinListOptions(t *testing.T) {
+	tests := []struct {
+		options string
+		key     string
+		want    string
+	}{
+		{options: "", key: "labels", want: ""},
+		{options: "title=x;labels=bug", key: "labels", want: "title=x;labels=bug"},
+		{options: "title=x;labels=bug;urgent", key: "labels", want: "title=x;labels=bug,urgent"},
+		{options: "labels=bug;urgent;assignees=johns", key: "labels", want: "labels=bug,urgent;assignees=johns"},
+		{options: "labels=bug,urgent", key: "labels", want: "labels=bug,urgent"},
+		{options: "title=x;y", key: "labels", want: "title=x;y"},
+		{options: "users=johns; jane", key: "users", want: "users=johns,jane"},
+	}
+	for _, tt := range tests {
+		if got := joinListOptions(tt.options, tt.key); got != tt.want {
+			t.Errorf("joinListOptions(%q, %q) = %q, want %q", tt.options, tt.key, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"github.com/rs/zerolog/log"
+)
+
+// repoPermissions are the repository permission levels, highest first
+var repoPermissions = []string{"admin", "maintain", "push", "triage", "pull"}
+
+// teamMembers lists the maintainers and members of the team, then its
+// pending invitations.
+func (g GithubActions) teamMembers() ([]string, string, error) {
+	var memberList []string
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	count := 0
+	for _, role := range []string{"maintainer", "member"} {
+		opts := &github.TeamListTeamMembersOptions{
+			Role:        role,
+			ListOptions: github.ListOptions{Page: 1, PerPage: 100},
+		}
+		var users []string
+		for {
+			members, resp, err := client.Teams.ListTeamMembersBySlug(ctx, g.Organization, g.Team.Slug, opts)
+			if err != nil {
+				return nil, "Internal Error", fmt.Errorf("getting members of team `%s` failed, Error: %s", g.Team.Slug, err)
+			}
+			for _, member := range members {
+				users = append(users, fmt.Sprintf("\t• *<%s|%s>*\n", member.GetHTMLURL(), member.GetLogin()))
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opts.ListOptions.Page = resp.NextPage
+		}
+		count += len(users)
+		memberList = append(memberList, fmt.Sprintf("*%ss* (%d)\n", role, len(users)))
+		memberList = append(memberList, users...)
+	}
+	lstopt := &github.ListOptions{
+		Page:    1,
+		PerPage: 100,
+	}
+	var invites []string
+	for {
+		invitations, resp, err := client.Teams.ListPendingTeamInvitationsBySlug(ctx, g.Organization, g.Team.Slug, lstopt)
+		if err != nil {
+			return nil, "Internal Error", fmt.Errorf("getting pending invitations of team `%s` failed, Error: %s", g.Team.Slug, err)
+		}
+		for _, invitation := range invitations {
+			invitee := invitation.GetLogin()
+			if len(invitee) == 0 {
+				invitee = invitation.GetEmail()
+			}
+			invites = append(invites, fmt.Sprintf("\t• `%s` invited by `%s` %s\n", invitee, invitation.GetInviter().GetLogin(), age(invitation.GetCreatedAt())))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		lstopt.Page = resp.NextPage
+	}
+	memberList = append(memberList, fmt.Sprintf("*pending invitations* (%d)\n", len(invites)))
+	memberList = append(memberList, invites...)
+	return memberList, fmt.Sprintf("%d member/s in team `%s`", count, g.Team.Slug), nil
+}
+
+// showTeam describes the team, its parent and child teams and the
+// repositories it can access.
+func (g GithubActions) showTeam() ([]string, string, error) {
+	var details []string
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	team, _, err := client.Teams.GetTeamBySlug(ctx, g.Organization, g.Team.Slug)
+	if err != nil {
+		return nil, "Unknown Team", fmt.Errorf("unable to find the team `%s`. Error: %s", g.Team.Slug, err)
+	}
+	parent := "none"
+	if team.Parent != nil {
+		parent = fmt.Sprintf("`%s`", team.Parent.GetSlug())
+	}
+	details = append(details, fmt.Sprintf("Description:\t*`%s`*\nPrivacy:\t*`%s`*\nMembers:\t*`%d`*\nParent team:\t%s\n", orNone(team.GetDescription()), team.GetPrivacy(), team.GetMembersCount(), parent))
+	lstopt := &github.ListOptions{
+		Page:    1,
+		PerPage: 100,
+	}
+	var children []string
+	for {
+		teams, resp, err := client.Teams.ListChildTeamsByParentSlug(ctx, g.Organization, g.Team.Slug, lstopt)
+		if err != nil {
+			return nil, "Internal Error", fmt.Errorf("getting child teams of `%s` failed, Error: %s", g.Team.Slug, err)
+		}
+		for _, child := range teams {
+			children = append(children, fmt.Sprintf("\t• `%s`\n", child.GetSlug()))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		lstopt.Page = resp.NextPage
+	}
+	details = append(details, fmt.Sprintf("*Child teams* (%d)\n", len(children)))
+	details = append(details, children...)
+	repos, err := g.teamRepos()
+	if err != nil {
+		return nil, "Internal Error", err
+	}
+	details = append(details, fmt.Sprintf("*Repositories* (%d)\n", len(repos)))
+	for _, repo := range repos {
+		details = append(details, fmt.Sprintf("\t• *<%s|%s>* `%s`\n", repo.GetHTMLURL(), repo.GetName(), repoPermission(repo.Permissions)))
+	}
+	return details, fmt.Sprintf("*<%s|%s>*", team.GetHTMLURL(), team.GetName()), nil
+}
+
+func (g GithubActions) listTeamRepos() ([]string, string, error) {
+	var repoList []string
+	repos, err := g.teamRepos()
+	if err != nil {
+		return nil, "Internal Error", err
+	}
+	for _, repo := range repos {
+		repoList = append(repoList, fmt.Sprintf("*<%s|%s>*\t`%s`\n", repo.GetHTMLURL(), repo.GetFullName(), repoPermission(repo.Permissions)))
+	}
+	if len(repoList) == 0 {
+		return repoList, fmt.Sprintf("team `%s` has no repositories", g.Team.Slug), nil
+	}
+	return repoList, fmt.Sprintf("team `%s` has access to %d repository/s", g.Team.Slug, len(repoList)), nil
+}
+
+// teamRepoPermission returns the permission of the team on the repository,
+// `none` when it has no access.
+func (g GithubActions) teamRepoPermission() (string, error) {
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	repo, rsp, err := client.Teams.IsTeamRepoBySlug(ctx, g.Organization, g.Team.Slug, g.Organization, g.Repository)
+	if err != nil {
+		if rsp != nil && rsp.StatusCode == 404 {
+			return "none", nil
+		}
+		return "", fmt.Errorf("unable to get the permission of team `%s` on `%s`. Error: %s", g.Team.Slug, g.Repository, err)
+	}
+	return repoPermission(repo.Permissions), nil
+}
+
+func (g GithubActions) grantTeamRepo() (string, error) {
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	if _, _, err := client.Teams.GetTeamBySlug(ctx, g.Organization, g.Team.Slug); err != nil {
+		return "Unknown Team", fmt.Errorf("unable to find the team `%s`. Error: %s", g.Team.Slug, err)
+	}
+	previous, err := g.teamRepoPermission()
+	if err != nil {
+		return "Internal Error", err
+	}
+	_, err = client.Teams.AddTeamRepoBySlug(ctx, g.Organization, g.Team.Slug, g.Organization, g.Repository, &github.TeamAddTeamRepoOptions{Permission: g.Team.Permission})
+	if err != nil {
+		return "Failed to Grant", fmt.Errorf("unable to grant `%s` on `%s` to team `%s`. Error: %s", g.Team.Permission, g.Repository, g.Team.Slug, err)
+	}
+	log.Info().Msg(fmt.Sprintf("Team %s granted %s on %s/%s, was %s", g.Team.Slug, g.Team.Permission, g.Organization, g.Repository, previous))
+	return fmt.Sprintf("team `%s` now has `%s` on `%s/%s` (was `%s`)", g.Team.Slug, g.Team.Permission, g.Organization, g.Repository, previous), nil
+}
+
+func (g GithubActions) revokeTeamRepo() (string, error) {
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	previous, err := g.teamRepoPermission()
+	if err != nil {
+		return "Internal Error", err
+	}
+	if previous == "none" {
+		return "No Access", fmt.Errorf("team `%s` has no access to `%s/%s`", g.Team.Slug, g.Organization, g.Repository)
+	}
+	_, err = client.Teams.RemoveTeamRepoBySlug(ctx, g.Organization, g.Team.Slug, g.Organization, g.Repository)
+	if err != nil {
+		return "Failed to Revoke", fmt.Errorf("unable to revoke the access of team `%s` to `%s`. Error: %s", g.Team.Slug, g.Repository, err)
+	}
+	log.Info().Msg(fmt.Sprintf("Team %s access to %s/%s revoked, was %s", g.Team.Slug, g.Organization, g.Repository, previous))
+	return fmt.Sprintf("team `%s` no longer has access to `%s/%s` (was `%s`)", g.Team.Slug, g.Organization, g.Repository, previous), nil
+}
+
+func (g GithubActions) teamRepos() ([]*github.Repository, error) {
+	var repos []*github.Repository
+	lstopt := &github.ListOptions{
+		Page:    1,
+		PerPage: 100,
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	for {
+		page, resp, err := client.Teams.ListTeamReposBySlug(ctx, g.Organization, g.Team.Slug, lstopt)
+		if err != nil {
+			return nil, fmt.Errorf("getting repositories of team `%s` failed, Error: %s", g.Team.Slug, err)
+		}
+		repos = append(repos, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		lstopt.Page = resp.NextPage
+	}
+	return repos, nil
+}
+
+// repoPermission returns the highest permission in the permissions map
+// GitHub returns for a repository.
+func repoPermission(permissions map[string]bool) string {
+	for _, permission := range repoPermissions {
+		if permissions[permission] {
+			return permission
+		}
+	}
+	return "none"
+}
+
+func (g GithubActions) createTeam() (string, error) {
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	newTeam := github.NewTeam{
+		Name:        g.Team.Slug,
+		Maintainers: g.Team.Maintainers,
+	}
+	if len(g.Team.Description) > 0 {
+		newTeam.Description = github.String(g.Team.Description)
+	}
+	if len(g.Team.Privacy) > 0 {
+		newTeam.Privacy = github.String(g.Team.Privacy)
+	}
+	if len(g.Team.Parent) > 0 {
+		parent, _, err := client.Teams.GetTeamBySlug(ctx, g.Organization, g.Team.Parent)
+		if err != nil {
+			return "Unknown Team", fmt.Errorf("unable to find the parent team `%s`. Error: %s", g.Team.Parent, err)
+		}
+		newTeam.ParentTeamID = parent.ID
+	}
+	for _, maintainer := range g.Team.Maintainers {
+		stat, reason, err := validateUser(maintainer, g.Organization)
+		if !stat {
+			return reason, fmt.Errorf("unable to get the user `%s` details, Error: %s", maintainer, err)
+		}
+	}
+	team, _, err := client.Teams.CreateTeam(ctx, g.Organization, newTeam)
+	if err != nil {
+		return "Failed to Create", fmt.Errorf("unable to create team `%s`. Error: %s", g.Team.Slug, err)
+	}
+	return fmt.Sprintf("team *<%s|%s>* created as `%s` (%s)", team.GetHTMLURL(), team.GetName(), team.GetSlug(), team.GetPrivacy()), nil
+}
+
+// teamDeletionPreview lists the members and repositories that lose access
+// when the team is deleted, for the confirmation message.
+func (g GithubActions) teamDeletionPreview() (string, []string, error) {
+	var details []string
+	stat, message, err := g.validateOrg()
+	if !stat {
+		return "", nil, fmt.Errorf("invalid org. %s Error: %s", message, err)
+	}
+	stat, err = g.validateInputs()
+	if !stat {
+		return "", nil, fmt.Errorf("unknown inputs, Error:%s", err)
+	}
+	children, err := g.descendantTeams(g.Team.Slug)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, child := range children {
+		if isProtectedTeam(child.GetSlug()) {
+			return "", nil, fmt.Errorf("You are not privileged to delete `%s`, deleting it deletes its child team `%s`", g.Team.Slug, child.GetSlug())
+		}
+	}
+	members, _, err := g.teamMembers()
+	if err != nil {
+		return "", nil, err
+	}
+	details = append(details, members...)
+	details = append(details, fmt.Sprintf("*child teams deleted with it* (%d)\n", len(children)))
+	for _, child := range children {
+		details = append(details, fmt.Sprintf("\t• `%s`\n", child.GetSlug()))
+	}
+	repos, err := g.teamRepos()
+	if err != nil {
+		return "", nil, err
+	}
+	details = append(details, fmt.Sprintf("*repositories* (%d)\n", len(repos)))
+	for _, repo := range repos {
+		details = append(details, fmt.Sprintf("\t• `%s` %s\n", repo.GetName(), repoPermission(repo.Permissions)))
+	}
+	return fmt.Sprintf("Delete team `%s` of `%s`? Its child teams are deleted too, and these members and repositories lose the access it gives:", g.Team.Slug, g.Organization), details, nil
+}
+
+// descendantTeams returns the child teams of the team and their child teams,
+// GitHub deletes all of them with the team.
+func (g GithubActions) descendantTeams(slug string) ([]*github.Team, error) {
+	var descendants []*github.Team
+	lstopt := &github.ListOptions{
+		Page:    1,
+		PerPage: 100,
+	}
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return nil, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	for {
+		teams, resp, err := client.Teams.ListChildTeamsByParentSlug(ctx, g.Organization, slug, lstopt)
+		if err != nil {
+			return nil, fmt.Errorf("getting child teams of `%s` failed, Error: %s", slug, err)
+		}
+		for _, child := range teams {
+			descendants = append(descendants, child)
+			grandchildren, err := g.descendantTeams(child.GetSlug())
+			if err != nil {
+				return nil, err
+			}
+			descendants = append(descendants, grandchildren...)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		lstopt.Page = resp.NextPage
+	}
+	return descendants, nil
+}
+
+func (g GithubActions) deleteTeam(confirmedBy string) (string, error) {
+	client, ctx, err := getGitClient(g.Organization)
+	if err != nil {
+		return "Internal Error", fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	// checked again, child teams may have been added since the preview
+	children, err := g.descendantTeams(g.Team.Slug)
+	if err != nil {
+		return "Internal Error", err
+	}
+	for _, child := range children {
+		if isProtectedTeam(child.GetSlug()) {
+			return "Not Privileged", fmt.Errorf("You are not privileged to delete `%s`, deleting it deletes its child team `%s`", g.Team.Slug, child.GetSlug())
+		}
+	}
+	_, err = client.Teams.DeleteTeamBySlug(ctx, g.Organization, g.Team.Slug)
+	if err != nil {
+		return "Failed to Delete", fmt.Errorf("unable to delete team `%s`. Error: %s", g.Team.Slug, err)
+	}
+	log.Info().Msg(fmt.Sprintf("Team %s deleted from the org %s, confirmed by %s", g.Team.Slug, g.Organization, confirmedBy))
+	return fmt.Sprintf("team `%s` deleted", g.Team.Slug), nil
+}
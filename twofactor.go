@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/slack-go/slack"
+	"os"
+	"sort"
+	"time"
+)
+
+// twoFactorReminderConfig reads the two-factor reminder settings from the
+// environment. The reminders are disabled when TWO_FACTOR_REMINDER_SCHEDULE
+// is not set.
+func twoFactorReminderConfig() (*cronSchedule, time.Time, string, error) {
+	spec := os.Getenv("TWO_FACTOR_REMINDER_SCHEDULE")
+	if len(spec) == 0 {
+		return nil, time.Time{}, "", nil
+	}
+	schedule, err := parseCronSchedule(spec)
+	if err != nil {
+		return nil, time.Time{}, "", fmt.Errorf("the environment variable TWO_FACTOR_REMINDER_SCHEDULE is invalid: %s", err)
+	}
+	deadline, err := time.Parse("2006-01-02", os.Getenv("TWO_FACTOR_DEADLINE"))
+	if err != nil {
+		return nil, time.Time{}, "", fmt.Errorf("the environment variable TWO_FACTOR_DEADLINE must be a date like 2006-01-02 when TWO_FACTOR_REMINDER_SCHEDULE is set")
+	}
+	return schedule, deadline, os.Getenv("TWO_FACTOR_REPORT_CHANNEL"), nil
+}
+
+// startTwoFactorReminders DMs the members and outside collaborators of the
+// default organization without two-factor authentication on the configured
+// schedule until ctx is done.
+func startTwoFactorReminders(ctx context.Context, client *slack.Client) {
+	schedule, deadline, channel, err := twoFactorReminderConfig()
+	if err != nil {
+		log.Info().Msg(err.Error())
+		return
+	}
+	if schedule == nil {
+		return
+	}
+	go runOnSchedule(ctx, "two-factor reminders", schedule, func() {
+		githubAct, err := commandTarget{}.resolveOrg()
+		if err != nil {
+			log.Info().Msg(fmt.Sprintf("Unable to send the two-factor reminders, Error: %s", err))
+			return
+		}
+		msg, unreached, err := githubAct.remindTwoFactor(client, deadline)
+		if err != nil {
+			log.Info().Msg(fmt.Sprintf("Unable to send the two-factor reminders, Error: %s", err))
+			return
+		}
+		log.Info().Msg(msg)
+		if len(channel) == 0 {
+			return
+		}
+		if err := postWithList(client, channel, msg, unreached); err != nil {
+			log.Info().Msg(fmt.Sprintf("Unable to post the two-factor reminders summary to %s, Error: %s", channel, err))
+		}
+	})
+}
+
+// twoFactorDisabled returns the members and the outside collaborators of the
+// organization without two-factor authentication. Only an owner of the
+// organization can filter on it.
+func (g GithubActions) twoFactorDisabled() ([]string, []string, error) {
+	members, err := g.orgMembers("2fa_disabled")
+	if err != nil {
+		return nil, nil, err
+	}
+	outside, err := g.outsideCollaborators("2fa_disabled")
+	if err != nil {
+		return nil, nil, err
+	}
+	var collaborators []string
+	for login := range outside {
+		collaborators = append(collaborators, login)
+	}
+	sort.Strings(members)
+	sort.Strings(collaborators)
+	return members, collaborators, nil
+}
+
+func (g GithubActions) twoFactorReport() (bool, report, error) {
+	members, collaborators, err := g.twoFactorDisabled()
+	if err != nil {
+		return false, report{}, err
+	}
+	result := report{
+		Summary: fmt.Sprintf("%d member/s and %d outside collaborator/s of `%s` have two-factor authentication disabled", len(members), len(collaborators), g.Organization),
+	}
+	if len(members) > 0 {
+		result.List = append(result.List, "*Members*\n")
+		for _, login := range members {
+			result.List = append(result.List, fmt.Sprintf("\t• `%s`\n", login))
+		}
+	}
+	if len(collaborators) > 0 {
+		result.List = append(result.List, "*Outside collaborators*\n")
+		for _, login := range collaborators {
+			result.List = append(result.List, fmt.Sprintf("\t• `%s`\n", login))
+		}
+	}
+	return true, result, nil
+}
+
+// remindTwoFactor DMs every user without two-factor authentication whose
+// Slack user is known, and returns the users it could not reach.
+func (g GithubActions) remindTwoFactor(client *slack.Client, deadline time.Time) (string, []string, error) {
+	var unreached []string
+	members, collaborators, err := g.twoFactorDisabled()
+	if err != nil {
+		return "", nil, err
+	}
+	reminded := 0
+	for _, login := range append(members, collaborators...) {
+		userID, err := slackUserForLogin(client, g.Organization, login)
+		if err != nil {
+			log.Info().Msg(err.Error())
+		}
+		if len(userID) == 0 {
+			unreached = append(unreached, fmt.Sprintf("\t• `%s`\n", login))
+			continue
+		}
+		text := fmt.Sprintf("Your GitHub account `%s` has access to `%s` without two-factor authentication. Please enable it in the security settings of your GitHub account before %s.", login, g.Organization, deadline.Format("2006-01-02"))
+		if time.Now().After(deadline) {
+			text = fmt.Sprintf("Your GitHub account `%s` has access to `%s` without two-factor authentication, the deadline to enable it was %s. Please enable it in the security settings of your GitHub account now.", login, g.Organization, deadline.Format("2006-01-02"))
+		}
+		if _, _, err := client.PostMessage(userID, slack.MsgOptionText(text, false)); err != nil {
+			log.Info().Msg(fmt.Sprintf("Unable to send the two-factor reminder to %s, Error: %s", login, err))
+			unreached = append(unreached, fmt.Sprintf("\t• `%s`\n", login))
+			continue
+		}
+		reminded++
+	}
+	msg := fmt.Sprintf("Two-factor reminders for `%s`: %d of %d user/s reminded", g.Organization, reminded, len(members)+len(collaborators))
+	if len(unreached) > 0 {
+		msg = msg + ", these could not be reached:"
+	}
+	return msg, unreached, nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTwoFactorReminderConfig(t *testing.T) {
+	tests := []struct {
+		schedule     string
+		deadline     string
+		channel      string
+		wantEnabled  bool
+		wantDeadline time.Time
+		wantErr      bool
+	}{
+		{},
+		{deadline: "2024-03-01", channel: "C1"},
+		{schedule: "0 9 * * 1", deadline: "2024-03-01", channel: "C1", wantEnabled: true, wantDeadline: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{schedule: "0 9 * * 1", deadline: "2024-03-01", wantEnabled: true, wantDeadline: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{schedule: "0 9 * * 1", wantErr: true},
+		{schedule: "0 9 * * 1", deadline: "01/03/2024", wantErr: true},
+		{schedule: "0 9 * *", deadline: "2024-03-01", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Setenv("TWO_FACTOR_REMINDER_SCHEDULE", tt.schedule)
+		t.Setenv("TWO_FACTOR_DEADLINE", tt.deadline)
+		t.Setenv("TWO_FACTOR_REPORT_CHANNEL", tt.channel)
+		schedule, deadline, channel, err := twoFactorReminderConfig()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("twoFactorReminderConfig() with %+v error %v, want error %v", tt, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if (schedule != nil) != tt.wantEnabled {
+			t.Errorf("twoFactorReminderConfig() with %+v enabled %t, want %t", tt, schedule != nil, tt.wantEnabled)
+		}
+		if tt.wantEnabled && (!deadline.Equal(tt.wantDeadline) || channel != tt.channel) {
+			t.Errorf("twoFactorReminderConfig() with %+v = %s, %q, want %s, %q", tt, deadline, channel, tt.wantDeadline, tt.channel)
+		}
+	}
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"fmt"
+	"github.com/google/go-github/v45/github"
+	"github.com/rs/zerolog/log"
+	"github.com/shomali11/slacker"
+	"github.com/slack-go/slack"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxUnfurlLines limits the code lines shown for a file link
+const maxUnfurlLines = 30
+
+// githubLink is a link to an issue, pull request, commit or file lines on
+// github.com or a configured enterprise host.
+type githubLink struct {
+	Kind      string // issue, pull, commit or blob
+	URL       string
+	Owner     string
+	Repo      string
+	Number    int
+	SHA       string
+	Ref       string
+	Path      string
+	StartLine int
+	EndLine   int
+}
+
+var githubPathPattern = regexp.MustCompile(`^/([^/]+)/([^/]+)/(issues|pull|commit|blob)/([^/]+)(/.*)?$`)
+var linePattern = regexp.MustCompile(`^L(\d+)(?:-L(\d+))?$`)
+var slackLinkPattern = regexp.MustCompile(`<(https?://[^>|]+)(?:\|[^>]*)?>|(https?://\S+)`)
+
+// githubHosts returns the hosts links are unfurled for.
+func githubHosts() []string {
+	hosts := []string{"github.com", "www.github.com"}
+	enterpriseURLs := []string{os.Getenv("GITHUB_ENTERPRISE_URL")}
+	if config, err := getConfig(); err == nil {
+		for _, org := range config.Organizations {
+			enterpriseURLs = append(enterpriseURLs, org.EnterpriseURL)
+		}
+	}
+	for _, rawURL := range enterpriseURLs {
+		if len(rawURL) == 0 {
+			continue
+		}
+		enterpriseURL, err := url.Parse(rawURL)
+		if err == nil && len(enterpriseURL.Hostname()) > 0 {
+			hosts = append(hosts, strings.ToLower(enterpriseURL.Hostname()))
+		}
+	}
+	return hosts
+}
+
+// parseGithubLinks finds the GitHub links in a Slack message text. Slack
+// wraps links as `<url>` or `<url|label>`.
+func parseGithubLinks(text string) []githubLink {
+	var links []githubLink
+	seen := make(map[string]bool)
+	for _, match := range slackLinkPattern.FindAllStringSubmatch(text, -1) {
+		raw := match[1]
+		if len(raw) == 0 {
+			raw = match[2]
+		}
+		if seen[raw] {
+			continue
+		}
+		seen[raw] = true
+		link, ok := parseGithubLink(raw)
+		if ok {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+func parseGithubLink(raw string) (githubLink, bool) {
+	link := githubLink{URL: raw}
+	u, err := url.Parse(raw)
+	if err != nil || !contains(githubHosts(), strings.ToLower(u.Hostname())) {
+		return link, false
+	}
+	parts := githubPathPattern.FindStringSubmatch(strings.TrimSuffix(u.Path, "/"))
+	if parts == nil {
+		return link, false
+	}
+	link.Owner, link.Repo, link.Kind = parts[1], parts[2], parts[3]
+	rest := strings.TrimPrefix(parts[5], "/")
+	switch link.Kind {
+	case "issues", "pull":
+		link.Number, err = strconv.Atoi(parts[4])
+		if err != nil {
+			return link, false
+		}
+		if link.Kind == "issues" {
+			link.Kind = "issue"
+		}
+		// a commit viewed inside a pull request
+		if link.Kind == "pull" && strings.HasPrefix(rest, "commits/") {
+			link.Kind = "commit"
+			link.SHA = strings.TrimPrefix(rest, "commits/")
+		}
+	case "commit":
+		link.SHA = parts[4]
+	case "blob":
+		link.Ref = parts[4]
+		link.Path = rest
+		if len(link.Path) == 0 {
+			return link, false
+		}
+		lines := linePattern.FindStringSubmatch(u.Fragment)
+		if lines == nil {
+			return link, false
+		}
+		link.StartLine, _ = strconv.Atoi(lines[1])
+		link.EndLine = link.StartLine
+		if len(lines[2]) > 0 {
+			link.EndLine, _ = strconv.Atoi(lines[2])
+		}
+		if link.EndLine < link.StartLine {
+			link.StartLine, link.EndLine = link.EndLine, link.StartLine
+		}
+		// lines are numbered from 1
+		if link.StartLine < 1 {
+			return link, false
+		}
+	}
+	return link, true
+}
+
+// unfurlLinks replies in the message thread with a card for every link of
+// an allowed repository, other links are left to Slack.
+func unfurlLinks(response slacker.ResponseWriter, links []githubLink) {
+	var attachments []slack.Attachment
+	for _, link := range links {
+		if !isAllowedRepo(link.Owner, link.Repo) {
+			continue
+		}
+		card, err := link.unfurlCard()
+		if err != nil {
+			log.Info().Msg(fmt.Sprintf("Unable to unfurl %s, Error: %s", link.URL, err))
+			continue
+		}
+		attachments = append(attachments, card)
+	}
+	if len(attachments) == 0 {
+		return
+	}
+	err := response.Reply("", slacker.WithAttachments(attachments), slacker.WithThreadReply(true))
+	if err != nil {
+		log.Info().Msg(fmt.Sprintf("Unable to send the slack message, Error: %s", err))
+	}
+}
+
+// unfurlCard builds the attachment shown for the link.
+func (l githubLink) unfurlCard() (slack.Attachment, error) {
+	switch l.Kind {
+	case "issue":
+		return l.issueCard()
+	case "pull":
+		return l.pullCard()
+	case "commit":
+		return l.commitCard()
+	case "blob":
+		return l.codeCard()
+	default:
+		return slack.Attachment{}, fmt.Errorf("unsupported link %s", l.URL)
+	}
+}
+
+func (l githubLink) issueCard() (slack.Attachment, error) {
+	client, ctx, err := getGitClient(l.Owner)
+	if err != nil {
+		return slack.Attachment{}, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	issue, _, err := client.Issues.Get(ctx, l.Owner, l.Repo, l.Number)
+	if err != nil {
+		return slack.Attachment{}, fmt.Errorf("unable to get issue `#%d`. Error: %s", l.Number, err)
+	}
+	// links to pull requests under /issues/ are common
+	if issue.IsPullRequest() {
+		l.Kind = "pull"
+		return l.pullCard()
+	}
+	return slack.Attachment{
+		Color:     stateColor(issue.GetState()),
+		Title:     fmt.Sprintf("#%d %s", issue.GetNumber(), issue.GetTitle()),
+		TitleLink: issue.GetHTMLURL(),
+		Footer:    fmt.Sprintf("%s/%s", l.Owner, l.Repo),
+		Fields: []slack.AttachmentField{
+			{Title: "State", Value: issue.GetState(), Short: true},
+			{Title: "Author", Value: issue.GetUser().GetLogin(), Short: true},
+			{Title: "Labels", Value: labelNames(issue.Labels), Short: true},
+			{Title: "Assignees", Value: userLogins(issue.Assignees), Short: true},
+		},
+	}, nil
+}
+
+func (l githubLink) pullCard() (slack.Attachment, error) {
+	client, ctx, err := getGitClient(l.Owner)
+	if err != nil {
+		return slack.Attachment{}, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	pull, _, err := client.PullRequests.Get(ctx, l.Owner, l.Repo, l.Number)
+	if err != nil {
+		return slack.Attachment{}, fmt.Errorf("unable to get pull request `#%d`. Error: %s", l.Number, err)
+	}
+	state := pull.GetState()
+	if pull.GetMerged() {
+		state = "merged"
+	} else if pull.GetDraft() {
+		state = "draft"
+	}
+	return slack.Attachment{
+		Color:     stateColor(state),
+		Title:     fmt.Sprintf("#%d %s", pull.GetNumber(), pull.GetTitle()),
+		TitleLink: pull.GetHTMLURL(),
+		Footer:    fmt.Sprintf("%s/%s", l.Owner, l.Repo),
+		Fields: []slack.AttachmentField{
+			{Title: "State", Value: state, Short: true},
+			{Title: "Author", Value: pull.GetUser().GetLogin(), Short: true},
+			{Title: "Labels", Value: labelNames(pull.Labels), Short: true},
+			{Title: "Assignees", Value: userLogins(pull.Assignees), Short: true},
+			{Title: "Reviews", Value: reviewSummary(l.Owner, l.Repo, l.Number), Short: true},
+			{Title: "CI", Value: ciSummary(l.Owner, l.Repo, pull.GetHead().GetSHA()), Short: true},
+		},
+	}, nil
+}
+
+func (l githubLink) commitCard() (slack.Attachment, error) {
+	client, ctx, err := getGitClient(l.Owner)
+	if err != nil {
+		return slack.Attachment{}, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	commit, _, err := client.Repositories.GetCommit(ctx, l.Owner, l.Repo, l.SHA, nil)
+	if err != nil {
+		return slack.Attachment{}, fmt.Errorf("unable to get commit `%s`. Error: %s", l.SHA, err)
+	}
+	author := commit.GetAuthor().GetLogin()
+	if len(author) == 0 {
+		author = commit.GetCommit().GetAuthor().GetName()
+	}
+	message := strings.SplitN(commit.GetCommit().GetMessage(), "\n", 2)[0]
+	return slack.Attachment{
+		Title:     fmt.Sprintf("%.7s %s", commit.GetSHA(), message),
+		TitleLink: commit.GetHTMLURL(),
+		Footer:    fmt.Sprintf("%s/%s", l.Owner, l.Repo),
+		Fields: []slack.AttachmentField{
+			{Title: "Author", Value: author, Short: true},
+			{Title: "Changes", Value: fmt.Sprintf("+%d -%d in %d file/s", commit.GetStats().GetAdditions(), commit.GetStats().GetDeletions(), len(commit.Files)), Short: true},
+			{Title: "CI", Value: ciSummary(l.Owner, l.Repo, commit.GetSHA()), Short: true},
+		},
+	}, nil
+}
+
+func (l githubLink) codeCard() (slack.Attachment, error) {
+	client, ctx, err := getGitClient(l.Owner)
+	if err != nil {
+		return slack.Attachment{}, fmt.Errorf("unable update New github client, Error: %s", err)
+	}
+	file, _, _, err := client.Repositories.GetContents(ctx, l.Owner, l.Repo, l.Path, &github.RepositoryContentGetOptions{Ref: l.Ref})
+	if err != nil || file == nil {
+		return slack.Attachment{}, fmt.Errorf("unable to get `%s` at `%s`. Error: %v", l.Path, l.Ref, err)
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return slack.Attachment{}, fmt.Errorf("unable to decode `%s`. Error: %s", l.Path, err)
+	}
+	lines := strings.Split(content, "\n")
+	start, end := lineRange(l.StartLine, l.EndLine, len(lines))
+	if start > len(lines) {
+		return slack.Attachment{}, fmt.Errorf("`%s` has only %d lines", l.Path, len(lines))
+	}
+	code := strings.ReplaceAll(strings.Join(lines[start-1:end], "\n"), "```", "` ` `")
+	title := fmt.Sprintf("%s lines %d-%d", l.Path, start, end)
+	if start == end {
+		title = fmt.Sprintf("%s line %d", l.Path, start)
+	}
+	return slack.Attachment{
+		Title:      title,
+		TitleLink:  l.URL,
+		Text:       fmt.Sprintf("```%s```", code),
+		Footer:     fmt.Sprintf("%s/%s@%s", l.Owner, l.Repo, l.Ref),
+		MarkdownIn: []string{"text"},
+	}, nil
+}
+
+// lineRange clamps the lines of a link to the file: start is at least 1 and
+// end is between start and count, at most maxUnfurlLines after start. A start
+// past the end of the file is returned as it is.
+func lineRange(start int, end int, count int) (int, int) {
+	if start < 1 {
+		start = 1
+	}
+	if start > count {
+		return start, start
+	}
+	if end < start {
+		end = start
+	}
+	if end > count {
+		end = count
+	}
+	if end-start+1 > maxUnfurlLines {
+		end = start + maxUnfurlLines - 1
+	}
+	return start, end
+}
+
+func stateColor(state string) string {
+	switch state {
+	case "open":
+		return "#2da44e"
+	case "merged":
+		return "#8250df"
+	case "closed":
+		return "#cf222e"
+	default:
+		return "#6e7781"
+	}
+}
+
+func labelNames(labels []*github.Label) string {
+	var names []string
+	for _, label := range labels {
+		names = append(names, label.GetName())
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+func userLogins(users []*github.User) string {
+	var logins []string
+	for _, user := range users {
+		logins = append(logins, user.GetLogin())
+	}
+	if len(logins) == 0 {
+		return "none"
+	}
+	return strings.Join(logins, ", ")
+}
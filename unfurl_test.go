@@ -0,0 +1,66 @@
+package main
+
+import "testing"
+
+func TestParseGithubLink(t *testing.T) {
+	tests := []struct {
+		raw   string
+		ok    bool
+		kind  string
+		num   int
+		sha   string
+		path  string
+		start int
+		end   int
+	}{
+		{raw: "https://github.com/org/repo/issues/12", ok: true, kind: "issue", num: 12},
+		{raw: "https://github.com/org/repo/pull/7/", ok: true, kind: "pull", num: 7},
+		{raw: "https://github.com/org/repo/pull/7/commits/abc123", ok: true, kind: "commit", num: 7, sha: "abc123"},
+		{raw: "https://github.com/org/repo/commit/abc123", ok: true, kind: "commit", sha: "abc123"},
+		{raw: "https://github.com/org/repo/blob/main/main.go#L10", ok: true, kind: "blob", path: "main.go", start: 10, end: 10},
+		{raw: "https://github.com/org/repo/blob/main/dir/main.go#L10-L20", ok: true, kind: "blob", path: "dir/main.go", start: 10, end: 20},
+		{raw: "https://github.com/org/repo/blob/main/main.go#L20-L10", ok: true, kind: "blob", path: "main.go", start: 10, end: 20},
+		{raw: "https://github.com/org/repo/blob/main/main.go#L0", ok: false},
+		{raw: "https://github.com/org/repo/blob/main/main.go#L5-L0", ok: false},
+		{raw: "https://github.com/org/repo/blob/main/main.go", ok: false},
+		{raw: "https://github.com/org/repo/blob/main#L1", ok: false},
+		{raw: "https://github.com/org/repo/issues/abc", ok: false},
+		{raw: "https://github.com/org/repo", ok: false},
+		{raw: "https://example.com/org/repo/issues/12", ok: false},
+	}
+	for _, tt := range tests {
+		link, ok := parseGithubLink(tt.raw)
+		if ok != tt.ok {
+			t.Errorf("parseGithubLink(%q) ok = %t, want %t", tt.raw, ok, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if link.Owner != "org" || link.Repo != "repo" || link.Kind != tt.kind || link.Number != tt.num || link.SHA != tt.sha || link.Path != tt.path || link.StartLine != tt.start || link.EndLine != tt.end {
+			t.Errorf("parseGithubLink(%q) = %+v", tt.raw, link)
+		}
+	}
+}
+
+func TestLineRange(t *testing.T) {
+	tests := []struct {
+		start, end, count int
+		wantStart         int
+		wantEnd           int
+	}{
+		{start: 1, end: 1, count: 10, wantStart: 1, wantEnd: 1},
+		{start: 0, end: 0, count: 10, wantStart: 1, wantEnd: 1},
+		{start: 0, end: 5, count: 10, wantStart: 1, wantEnd: 5},
+		{start: 5, end: 2, count: 10, wantStart: 5, wantEnd: 5},
+		{start: 5, end: 50, count: 10, wantStart: 5, wantEnd: 10},
+		{start: 1, end: 100, count: 100, wantStart: 1, wantEnd: maxUnfurlLines},
+		{start: 20, end: 30, count: 10, wantStart: 20, wantEnd: 20},
+	}
+	for _, tt := range tests {
+		start, end := lineRange(tt.start, tt.end, tt.count)
+		if start != tt.wantStart || end != tt.wantEnd {
+			t.Errorf("lineRange(%d, %d, %d) = %d, %d, want %d, %d", tt.start, tt.end, tt.count, start, end, tt.wantStart, tt.wantEnd)
+		}
+	}
+}